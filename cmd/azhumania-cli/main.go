@@ -0,0 +1,81 @@
+// azhumania-cli запускает бота в терминале: сообщения вводятся с клавиатуры и проходят
+// через тот же сервис, что и в Telegram. Нужны только PostgreSQL и Redis
+package main
+
+import (
+	"azhumania/internal/bot/cli"
+	"azhumania/internal/config"
+	"azhumania/internal/logging"
+	"azhumania/internal/service"
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/rs/zerolog"
+)
+
+func main() {
+	// Логи идут в stderr, чтобы не смешиваться с диалогом
+	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
+
+	user := cli.User{}
+	var files string
+	var jobs bool
+	cfg, err := config.Load(os.Args[1:], config.WithoutTelegram(), config.WithFlags(func(fs *flag.FlagSet) {
+		fs.Int64Var(&user.ID, "user-id", 1, "Telegram ID of the fake user")
+		fs.StringVar(&user.FirstName, "user-name", "Тестер", "first name of the fake user")
+		fs.StringVar(&user.UserName, "user-username", "tester", "username of the fake user")
+		fs.StringVar(&files, "files", os.TempDir(), "directory for photos and documents sent by the bot")
+		fs.BoolVar(&jobs, "jobs", false, "run background jobs; they claim rest timers and digests of all users in the database")
+	}))
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load config")
+	}
+
+	configured, err := logging.New(cfg.Log, os.Stderr)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to configure logging")
+	}
+	logger = configured
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	svc, err := service.New(ctx, cfg, &logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to start service")
+	}
+
+	chat := cli.New(svc, user, os.Stdin, os.Stdout, files, &logger)
+
+	// Фоновые задачи общие для всех пользователей: запущенные здесь, они забрали бы напоминания
+	// и сводки пользователей настоящего бота с той же базой. Поэтому задачи запускаются только
+	// по флагу -jobs, а в чат выводятся лишь сообщения фейковому пользователю
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		if jobs {
+			svc.RunJobs(jobsCtx, chat)
+		}
+	}()
+
+	if err := chat.Run(ctx); err != nil {
+		logger.Error().Err(err).Msg("failed to read input")
+	}
+	stopJobs()
+	<-jobsDone
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := svc.Close(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("failed to close service")
+	}
+}
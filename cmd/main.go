@@ -1,31 +1,114 @@
 package main
 
 import (
+	"azhumania/internal/api"
 	"azhumania/internal/bot/telegram"
+	"azhumania/internal/config"
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
 	"azhumania/internal/service"
+	"context"
+	"errors"
+	"flag"
+	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/rs/zerolog"
 )
 
-const (
-	apiKey = "7887768155:AAHrGzYl8Qic0mygjmzFTfvsonY8NIuS9dg"
-
-	psql_dsn       = "host=192.168.0.14 port=5431 user=azhumania password=Asdflkjh12 dbname=azhumania"
-	redis_host     = "192.168.0.14:55000"
-	redis_username = "default"
-	redis_password = "redispw"
-	redis_db       = 0
-)
-
 func main() {
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
-	svc, err := service.New(psql_dsn, redis_host, redis_username, redis_password, redis_db, &logger)
+
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load config")
+	}
+
+	configured, err := logging.New(cfg.Log, os.Stdout)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to configure logging")
+	}
+	logger = configured
+	// Сообщения сторонних библиотек, пишущих через стандартный log, идут в тот же поток
+	log.SetFlags(0)
+	log.SetOutput(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	svc, err := service.New(ctx, cfg, &logger)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to start service")
+	}
+
+	tg_bot, err := telegram.New(cfg.Telegram, svc, &logger)
 	if err != nil {
-		panic(err)
+		logger.Fatal().Err(err).Msg("failed to create telegram bot")
+	}
+
+	var httpServer *api.Server
+	if cfg.HTTP.Enabled {
+		httpServer = api.NewServer(cfg.HTTP.Listen, &logger)
+
+		var checks []api.Check
+		for _, check := range svc.HealthChecks() {
+			checks = append(checks, api.Check{Name: check.Name, Ping: check.Ping})
+		}
+		checks = append(checks, api.Check{Name: "telegram", Ping: tg_bot.Ping})
+		api.NewHealth(&logger, checks...).Register(httpServer)
+
+		if cfg.HTTP.API {
+			httpServer.Handle("/api/", svc.APIHandler())
+		}
+		if cfg.HTTP.Metrics {
+			httpServer.Handle("/metrics", metrics.Handler())
+		}
+		if cfg.HTTP.PublicURL != "" {
+			httpServer.Handle("/charts/", svc.ChartsHandler())
+		}
+		if err := httpServer.Start(); err != nil {
+			logger.Fatal().Err(err).Msg("failed to start http server")
+		}
+	}
+
+	// Фоновые задачи отправляют сообщения сами и не занимают воркеры обновлений
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		svc.RunJobs(ctx, tg_bot)
+	}()
+
+	if err := tg_bot.Listen(ctx); err != nil {
+		logger.Error().Err(err).Msg("failed to receive updates")
 	}
 
-	tg_bot := telegram.New(apiKey, svc)
+	logger.Info().Msg("shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
 
-	tg_bot.Listen()
+	if err := tg_bot.Shutdown(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("failed to drain updates")
+	}
+	select {
+	case <-jobsDone:
+	case <-shutdownCtx.Done():
+		logger.Error().Msg("background jobs did not stop in time")
+	}
+	if err := tg_bot.Close(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("failed to send queued messages")
+	}
+	if httpServer != nil {
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			logger.Error().Err(err).Msg("failed to stop http server")
+		}
+	}
+	if err := svc.Close(shutdownCtx); err != nil {
+		logger.Error().Err(err).Msg("failed to close service")
+	}
 }
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"azhumania/internal/application/services"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Charts отдает картинки графиков, на которые ссылаются результаты inline режима.
+// ID графика случайный и живет несколько минут, поэтому авторизация не нужна
+type Charts struct {
+	shareService *services.ShareService
+	logger       *zerolog.Logger
+}
+
+// NewCharts создает обработчик графиков. Путь: /charts/{id}.jpg
+func NewCharts(shareService *services.ShareService, logger *zerolog.Logger) http.Handler {
+	charts := &Charts{
+		shareService: shareService,
+		logger:       logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /charts/{file}", charts.handleChart)
+
+	return mux
+}
+
+func (c *Charts) handleChart(w http.ResponseWriter, r *http.Request) {
+	id, ok := strings.CutSuffix(r.PathValue("file"), ".jpg")
+	if !ok || id == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := c.shareService.Chart(r.Context(), id)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("failed to get chart")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if data == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "public, max-age=600")
+	w.Write(data)
+}
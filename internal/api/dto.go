@@ -0,0 +1,107 @@
+package api
+
+import (
+	"azhumania/internal/domain/models"
+	"time"
+)
+
+// dateLayout формат дат в параметрах и ответах API
+const dateLayout = "2006-01-02"
+
+type addApproachRequest struct {
+	Count int `json:"count"`
+}
+
+type approachResponse struct {
+	Count     int        `json:"count"`
+	CreatedAt *time.Time `json:"created_at,omitempty"` // нет у старых подходов, записанных без времени
+}
+
+type sessionResponse struct {
+	Date               string             `json:"date"`
+	TotalCount         int                `json:"total_count"`
+	ApproachCount      int                `json:"approach_count"`
+	AveragePerApproach float64            `json:"average_per_approach"`
+	Approaches         []approachResponse `json:"approaches"`
+}
+
+type weeklyStatsResponse struct {
+	WeekStart     string  `json:"week_start"`
+	WeekEnd       string  `json:"week_end"`
+	TotalCount    int     `json:"total_count"`
+	TrainingDays  int     `json:"training_days"`
+	AveragePerDay float64 `json:"average_per_day"`
+	BestDay       int     `json:"best_day"`
+}
+
+type monthlyStatsResponse struct {
+	Month         string  `json:"month"`
+	TotalCount    int     `json:"total_count"`
+	TrainingDays  int     `json:"training_days"`
+	AveragePerDay float64 `json:"average_per_day"`
+	BestDay       int     `json:"best_day"`
+	Streak        int     `json:"streak"`
+}
+
+type historyResponse struct {
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Sessions []sessionResponse `json:"sessions"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newSessionResponse(session *models.PushupSession) sessionResponse {
+	approaches := make([]approachResponse, 0, len(session.Approaches))
+	for _, approach := range session.Approaches {
+		response := approachResponse{Count: approach.Count}
+		if !approach.CreatedAt.IsZero() {
+			createdAt := approach.CreatedAt
+			response.CreatedAt = &createdAt
+		}
+		approaches = append(approaches, response)
+	}
+
+	return sessionResponse{
+		Date:               session.Date.Format(dateLayout),
+		TotalCount:         session.GetTotalCount(),
+		ApproachCount:      session.GetApproachCount(),
+		AveragePerApproach: session.GetAveragePerApproach(),
+		Approaches:         approaches,
+	}
+}
+
+func newWeeklyStatsResponse(stats *models.PeriodStats) weeklyStatsResponse {
+	return weeklyStatsResponse{
+		WeekStart:     stats.Period.From.Format(dateLayout),
+		WeekEnd:       stats.Period.To.Format(dateLayout),
+		TotalCount:    stats.TotalCount,
+		TrainingDays:  stats.TrainingDays,
+		AveragePerDay: stats.AveragePerDay,
+		BestDay:       stats.BestDay,
+	}
+}
+
+func newMonthlyStatsResponse(stats *models.MonthlyStats) monthlyStatsResponse {
+	return monthlyStatsResponse{
+		Month:         stats.Month.Format("2006-01"),
+		TotalCount:    stats.TotalCount,
+		TrainingDays:  stats.TrainingDays,
+		AveragePerDay: stats.AveragePerDay,
+		BestDay:       stats.BestDay,
+		Streak:        stats.Streak,
+	}
+}
+
+type healthResponse struct {
+	Status string                   `json:"status"`
+	Checks map[string]checkResponse `json:"checks"`
+}
+
+type checkResponse struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
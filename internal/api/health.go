@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// checkTimeout ограничивает время одной проверки зависимости
+const checkTimeout = 2 * time.Second
+
+// Статусы проверок
+const (
+	statusOK          = "ok"
+	statusUnavailable = "unavailable"
+)
+
+// Check проверка доступности одной зависимости
+type Check struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
+// Health обслуживает /healthz и /readyz
+type Health struct {
+	checks []Check
+	logger *zerolog.Logger
+}
+
+// NewHealth создает обработчик проверок здоровья
+func NewHealth(logger *zerolog.Logger, checks ...Check) *Health {
+	return &Health{
+		checks: checks,
+		logger: logger,
+	}
+}
+
+// Liveness отвечает 200, пока процесс обслуживает запросы. Состояние зависимостей
+// включается в ответ, но не влияет на код: перезапуск бота не поднимет упавшую базу
+func (h *Health) Liveness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.run(r.Context())
+		report.Status = statusOK
+		writeJSON(w, http.StatusOK, report)
+	})
+}
+
+// Readiness отвечает 503, если хотя бы одна зависимость недоступна
+func (h *Health) Readiness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.run(r.Context())
+
+		status := http.StatusOK
+		if report.Status != statusOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+// Register подключает /healthz и /readyz к серверу
+func (h *Health) Register(s *Server) {
+	s.Handle("GET /healthz", h.Liveness())
+	s.Handle("GET /readyz", h.Readiness())
+}
+
+// run выполняет все проверки параллельно
+func (h *Health) run(ctx context.Context) healthResponse {
+	results := make([]checkResponse, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.check(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := healthResponse{
+		Status: statusOK,
+		Checks: make(map[string]checkResponse, len(results)),
+	}
+	for i, result := range results {
+		if result.Status != statusOK {
+			report.Status = statusUnavailable
+		}
+		report.Checks[h.checks[i].Name] = result
+	}
+
+	return report
+}
+
+func (h *Health) check(ctx context.Context, check Check) checkResponse {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	started := time.Now()
+	err := check.Ping(ctx)
+	result := checkResponse{
+		Status:    statusOK,
+		LatencyMS: float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if err != nil {
+		h.logger.Warn().Err(err).Str("dependency", check.Name).Msg("health check failed")
+		result.Status = statusUnavailable
+		result.Error = err.Error()
+	}
+
+	return result
+}
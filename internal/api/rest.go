@@ -0,0 +1,254 @@
+package api
+
+import (
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/application/services"
+	domainErrors "azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// maxHistoryDays максимальная длина периода в запросе истории
+const maxHistoryDays = 366
+
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+type userKey struct{}
+
+// REST обслуживает JSON API для записи подходов и получения статистики
+type REST struct {
+	pushupService *services.PushupService
+	tokenService  *services.TokenService
+	userService   *services.UserService
+	limiter       ratelimit.Limiter // nil — частота запросов не ограничена
+	limit         ratelimit.Limit
+	logger        *zerolog.Logger
+}
+
+// NewREST создает обработчик REST API. Все пути начинаются с /api/v1/. limiter ограничивает
+// частоту запросов с одним токеном лимитом limit, nil отключает ограничение
+func NewREST(
+	pushupService *services.PushupService,
+	tokenService *services.TokenService,
+	userService *services.UserService,
+	limiter ratelimit.Limiter,
+	limit ratelimit.Limit,
+	logger *zerolog.Logger,
+) http.Handler {
+	api := &REST{
+		pushupService: pushupService,
+		tokenService:  tokenService,
+		userService:   userService,
+		limiter:       limiter,
+		limit:         limit,
+		logger:        logger,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/openapi.yaml", api.handleOpenAPI)
+	mux.Handle("POST /api/v1/approaches", api.authenticate(api.handleAddApproach))
+	mux.Handle("GET /api/v1/stats/today", api.authenticate(api.handleToday))
+	mux.Handle("GET /api/v1/stats/weekly", api.authenticate(api.handleWeekly))
+	mux.Handle("GET /api/v1/stats/monthly", api.authenticate(api.handleMonthly))
+	mux.Handle("GET /api/v1/history", api.authenticate(api.handleHistory))
+
+	return mux
+}
+
+// authenticate проверяет токен из заголовка Authorization: Bearer <token>. Лимит частоты
+// проверяется до обращения к БД, поэтому перебор токенов тоже ограничен. Заблокированным
+// пользователям API недоступно, как и бот
+func (a *REST) authenticate(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		token = strings.TrimSpace(token)
+
+		if !a.allow(r.Context(), token) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(a.limit.Every.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "too many requests")
+			return
+		}
+
+		userID, err := a.tokenService.Authenticate(r.Context(), token)
+		if err != nil {
+			if errors.Is(err, domainErrors.ErrInvalidToken) {
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			a.logger.Error().Err(err).Msg("failed to authenticate api request")
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		user, err := a.userService.GetUser(r.Context(), userID)
+		if err != nil {
+			if errors.Is(err, domainErrors.ErrUserNotFound) {
+				writeError(w, http.StatusUnauthorized, "invalid token")
+				return
+			}
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if user.IsBanned() {
+			writeError(w, http.StatusForbidden, "user is banned")
+			return
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// allow забирает токен из корзины лимита API для token. В ключе хранится хэш, а не сам токен.
+// При ошибке хранилища лимитов запрос пропускается, как и сообщения в боте
+func (a *REST) allow(ctx context.Context, token string) bool {
+	if a.limiter == nil {
+		return true
+	}
+
+	allowed, err := a.limiter.Allow(ctx, fmt.Sprintf("api:%x", sha256.Sum256([]byte(token))), a.limit)
+	if err != nil {
+		a.logger.Error().Err(err).Msg("failed to check api rate limit")
+		return true
+	}
+	return allowed
+}
+
+func (a *REST) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(openAPISpec)
+}
+
+func (a *REST) handleAddApproach(w http.ResponseWriter, r *http.Request) {
+	var req addApproachRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	session, _, err := a.pushupService.AddPushupApproach(r.Context(), requestUser(r), req.Count)
+	if err != nil {
+		switch {
+		case errors.Is(err, domainErrors.ErrInvalidPushupCount):
+			writeError(w, http.StatusBadRequest, "count must be greater than 0")
+		case errors.Is(err, domainErrors.ErrPushupCountTooHigh):
+			writeError(w, http.StatusBadRequest, "count must not exceed 1000")
+		default:
+			writeError(w, http.StatusInternalServerError, "internal error")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, newSessionResponse(session))
+}
+
+func (a *REST) handleToday(w http.ResponseWriter, r *http.Request) {
+	session, err := a.pushupService.GetTodayStats(r.Context(), requestUser(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newSessionResponse(session))
+}
+
+// handleWeekly возвращает статистику за текущую неделю с первым днем из настройки пользователя
+func (a *REST) handleWeekly(w http.ResponseWriter, r *http.Request) {
+	stats, err := a.pushupService.GetWeeklyStats(r.Context(), requestUser(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newWeeklyStatsResponse(stats))
+}
+
+func (a *REST) handleMonthly(w http.ResponseWriter, r *http.Request) {
+	stats, err := a.pushupService.GetMonthlyStats(r.Context(), requestUser(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newMonthlyStatsResponse(stats))
+}
+
+// handleHistory возвращает сессии за период [from, to] включительно, по умолчанию за последние
+// 30 дней до сегодняшнего дня пользователя
+func (a *REST) handleHistory(w http.ResponseWriter, r *http.Request) {
+	user := requestUser(r)
+	today := user.Today()
+	from, to := today.AddDate(0, 0, -29), today
+
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse(dateLayout, value); err != nil {
+			writeError(w, http.StatusBadRequest, "from must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse(dateLayout, value); err != nil {
+			writeError(w, http.StatusBadRequest, "to must be a date in YYYY-MM-DD format")
+			return
+		}
+	}
+
+	if to.Before(from) {
+		writeError(w, http.StatusBadRequest, "from must not be after to")
+		return
+	}
+	if models.NewPeriod(from, to).Days() > maxHistoryDays {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("period must not exceed %d days", maxHistoryDays))
+		return
+	}
+
+	sessions, err := a.pushupService.GetHistory(r.Context(), user.ID, from, to)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	resp := historyResponse{
+		From:     from.Format(dateLayout),
+		To:       to.Format(dateLayout),
+		Sessions: make([]sessionResponse, 0, len(sessions)),
+	}
+	for _, session := range sessions {
+		resp.Sessions = append(resp.Sessions, newSessionResponse(session))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// requestUser возвращает пользователя, установленного authenticate
+func requestUser(r *http.Request) *models.User {
+	user, _ := r.Context().Value(userKey{}).(*models.User)
+	return user
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Server HTTP сервер приложения: REST API и служебные эндпоинты
+type Server struct {
+	server *http.Server
+	mux    *http.ServeMux
+	logger *zerolog.Logger
+}
+
+// NewServer создает HTTP сервер, слушающий addr
+func NewServer(addr string, logger *zerolog.Logger) *Server {
+	mux := http.NewServeMux()
+
+	return &Server{
+		server: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		mux:    mux,
+		logger: logger,
+	}
+}
+
+// Handle регистрирует обработчик для шаблона пути
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start открывает порт и начинает обслуживать запросы в отдельной горутине.
+// Ошибка занятого порта возвращается сразу
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error().Err(err).Msg("http server stopped")
+		}
+	}()
+
+	s.logger.Info().Str("addr", listener.Addr().String()).Msg("http server started")
+
+	return nil
+}
+
+// Shutdown останавливает сервер, дожидаясь завершения активных запросов
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
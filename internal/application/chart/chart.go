@@ -0,0 +1,65 @@
+// Package chart рисует графики для отправки картинкой. Используется только стандартная
+// библиотека, поэтому подписей на картинке нет: числа передаются в подписи к фото
+package chart
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+// Размеры картинки недели. Telegram показывает превью inline результатов примерно 16:9
+const (
+	width   = 640
+	height  = 360
+	padding = 32
+	gap     = 16
+)
+
+var (
+	background = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	baseline   = color.RGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff}
+	bar        = color.RGBA{R: 0x4a, G: 0x90, B: 0xe2, A: 0xff}
+	highlight  = color.RGBA{R: 0xf5, G: 0xa6, B: 0x23, A: 0xff}
+	empty      = color.RGBA{R: 0xee, G: 0xee, B: 0xee, A: 0xff}
+)
+
+// WeekBars рисует столбики отжиманий по дням недели с ее первого дня и возвращает JPEG.
+// Столбик дня selected выделяется цветом, -1 — без выделения
+func WeekBars(days [7]int, selected int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	best := 0
+	for _, count := range days {
+		best = max(best, count)
+	}
+
+	bottom := height - padding
+	chartHeight := bottom - padding
+	barWidth := (width - 2*padding - gap*(len(days)-1)) / len(days)
+
+	for i, count := range days {
+		left := padding + i*(barWidth+gap)
+
+		// Пустой день отмечается низкой серой полоской, чтобы было видно, что он есть
+		barHeight, fill := 4, color.Color(empty)
+		if count > 0 && best > 0 {
+			barHeight = max(barHeight, count*chartHeight/best)
+			fill = bar
+			if i == selected {
+				fill = highlight
+			}
+		}
+		draw.Draw(img, image.Rect(left, bottom-barHeight, left+barWidth, bottom), &image.Uniform{C: fill}, image.Point{}, draw.Src)
+	}
+	draw.Draw(img, image.Rect(padding/2, bottom, width-padding/2, bottom+2), &image.Uniform{C: baseline}, image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,259 @@
+// Package entry разбирает сообщение с отжиманиями: одно число, сумму "15+15+10",
+// множитель "3x20" или список "15 20 10". Числа можно писать словами по-русски и по-английски:
+// "двадцать пять", "2 десятка", "fifteen". Каждая часть становится отдельным подходом
+package entry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxApproaches наибольшее число подходов в одном сообщении. Защищает от "1000x1000"
+const MaxApproaches = 50
+
+// Part один подход из сообщения
+type Part struct {
+	Count int
+	Text  string // часть сообщения, из которой получен подход, например "3x20"
+	Sets  int    // сколько подходов дала часть Text: 3 для "3x20", 1 для одного числа
+}
+
+// ErrorKind причина, по которой сообщение не удалось разобрать
+type ErrorKind int
+
+const (
+	// ErrorUnexpected в сообщении есть символ или слово, которые не являются числом
+	ErrorUnexpected ErrorKind = iota
+	// ErrorMissingNumber у знака умножения нет числа с одной из сторон
+	ErrorMissingNumber
+	// ErrorTooManyApproaches подходов больше MaxApproaches
+	ErrorTooManyApproaches
+	// ErrorNumberTooLarge число не помещается в int
+	ErrorNumberTooLarge
+)
+
+// Error ошибка разбора с указанием части сообщения, которая ее вызвала
+type Error struct {
+	Kind   ErrorKind
+	Token  string // часть сообщения с ошибкой
+	Offset int    // позиция Token в сообщении в символах, начиная с 0
+}
+
+func (e *Error) Error() string {
+	switch e.Kind {
+	case ErrorMissingNumber:
+		return fmt.Sprintf("entry: missing number next to %q at %d", e.Token, e.Offset)
+	case ErrorTooManyApproaches:
+		return fmt.Sprintf("entry: more than %d approaches at %q", MaxApproaches, e.Token)
+	case ErrorNumberTooLarge:
+		return fmt.Sprintf("entry: number %q at %d is too large", e.Token, e.Offset)
+	default:
+		return fmt.Sprintf("entry: unexpected %q at %d", e.Token, e.Offset)
+	}
+}
+
+// HasNumber сообщает, похож ли текст на запись отжиманий. Сообщения без цифр и числительных —
+// обычная переписка, на них не нужно отвечать ошибкой разбора
+func HasNumber(text string) bool {
+	if strings.IndexFunc(text, isDigit) >= 0 {
+		return true
+	}
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if isNumberWord(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse разбирает сообщение на подходы. Части разделяются пробелами, "+", "," или ";",
+// множитель записывается как "3x20", "3х20", "3×20" или "3*20" — три подхода по 20.
+// Проверка количества в каждом подходе остается за доменом
+func Parse(text string) ([]Part, error) {
+	tokens, err := scan(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []Part
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case tokenSeparator:
+			continue
+		case tokenMultiply:
+			return nil, &Error{Kind: ErrorMissingNumber, Token: tok.text, Offset: tok.offset}
+		case tokenOther:
+			return nil, &Error{Kind: ErrorUnexpected, Token: tok.text, Offset: tok.offset}
+		}
+
+		// tok — число. Если за ним идет множитель, это подходы вида "3x20"
+		if i+1 >= len(tokens) || tokens[i+1].kind != tokenMultiply {
+			parts = append(parts, Part{Count: tok.value, Text: tok.text, Sets: 1})
+		} else {
+			mul := tokens[i+1]
+			if i+2 >= len(tokens) || tokens[i+2].kind != tokenNumber {
+				return nil, &Error{Kind: ErrorMissingNumber, Token: mul.text, Offset: mul.offset}
+			}
+			count := tokens[i+2]
+			i += 2
+
+			partText := string(tok.source[tok.offset:count.end()])
+			if tok.value > MaxApproaches {
+				return nil, &Error{Kind: ErrorTooManyApproaches, Token: partText, Offset: tok.offset}
+			}
+			if tok.value == 0 {
+				return nil, &Error{Kind: ErrorUnexpected, Token: partText, Offset: tok.offset}
+			}
+			for j := 0; j < tok.value; j++ {
+				parts = append(parts, Part{Count: count.value, Text: partText, Sets: tok.value})
+			}
+		}
+
+		if len(parts) > MaxApproaches {
+			return nil, &Error{Kind: ErrorTooManyApproaches, Token: parts[len(parts)-1].Text, Offset: tok.offset}
+		}
+	}
+
+	if len(parts) == 0 {
+		return nil, &Error{Kind: ErrorUnexpected, Token: strings.TrimSpace(text)}
+	}
+
+	return parts, nil
+}
+
+// Multiplied возвращает части с множителем по одной на каждую, например "3x20" для трех
+// подходов по 20. По ним ответ показывает, как понято сообщение: "20x3" — двадцать подходов по 3
+func Multiplied(parts []Part) []Part {
+	var multiplied []Part
+	for i := 0; i < len(parts); i += max(parts[i].Sets, 1) {
+		if parts[i].Sets > 1 {
+			multiplied = append(multiplied, parts[i])
+		}
+	}
+	return multiplied
+}
+
+// Counts возвращает количество отжиманий в каждом подходе
+func Counts(parts []Part) []int {
+	counts := make([]int, len(parts))
+	for i, part := range parts {
+		counts[i] = part.Count
+	}
+	return counts
+}
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenMultiply
+	tokenSeparator
+	tokenWord // слово до разбора числительных, после combineWords не остается
+	tokenOther
+)
+
+type token struct {
+	kind   tokenKind
+	text   string
+	value  int    // для tokenNumber
+	offset int    // в символах
+	source []rune // все сообщение, чтобы собрать текст составного числа
+}
+
+// end позиция символа после токена
+func (t token) end() int {
+	return t.offset + utf8.RuneCountInString(t.text)
+}
+
+// multiplySigns знаки умножения. Буквы x и х (латинская и кириллическая) считаются
+// множителем, только если стоят отдельно от других букв. "по" — как в "3 по 20"
+var multiplySigns = map[string]bool{
+	"x": true, "X": true, "х": true, "Х": true, "×": true, "*": true, "по": true, "По": true,
+}
+
+// separatorWords слова, которые разделяют подходы так же, как "+"
+var separatorWords = map[string]bool{"и": true, "плюс": true, "plus": true}
+
+// scan делит текст на числа, знаки умножения, разделители и все остальное. Пробелы не
+// становятся токенами: "15 20" — два числа подряд, "3 x 20" — то же, что "3x20".
+// Числительные заменяются числами
+func scan(text string) ([]token, error) {
+	var tokens []token
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+
+		case isDigit(r):
+			for i < len(runes) && isDigit(runes[i]) {
+				i++
+			}
+			digits := string(runes[start:i])
+			value, err := strconv.Atoi(digits)
+			if err != nil {
+				return nil, &Error{Kind: ErrorNumberTooLarge, Token: digits, Offset: start}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: digits, value: value, offset: start, source: runes})
+
+		case r == '+' || r == ',' || r == ';':
+			i++
+			tokens = append(tokens, token{kind: tokenSeparator, text: string(r), offset: start, source: runes})
+
+		case unicode.IsLetter(r):
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			kind := tokenWord
+			switch {
+			case multiplySigns[word]:
+				kind = tokenMultiply
+			case separatorWords[normalizeWord(word)]:
+				kind = tokenSeparator
+			}
+			tokens = append(tokens, token{kind: kind, text: word, offset: start, source: runes})
+
+		case r == '-' && i > 0 && unicode.IsLetter(runes[i-1]) && i+1 < len(runes) && unicode.IsLetter(runes[i+1]):
+			// Дефис внутри числительного: "twenty-five"
+			i++
+			continue
+
+		default:
+			i++
+			kind := tokenOther
+			if multiplySigns[string(r)] {
+				kind = tokenMultiply
+			}
+			tokens = append(tokens, token{kind: kind, text: string(r), offset: start, source: runes})
+		}
+	}
+
+	return combineWords(tokens), nil
+}
+
+// isDigit сообщает, является ли r цифрой 0-9. Другие цифры Unicode, например "١٥" или
+// полноширинные "１５", strconv не разбирает, поэтому они остаются непонятными символами
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// Highlight возвращает сообщение, в котором часть с ошибкой выделена скобками: "15+«abc»"
+func Highlight(text string, err *Error) string {
+	runes := []rune(text)
+	start := err.Offset
+	end := start + utf8.RuneCountInString(err.Token)
+	if err.Token == "" || start < 0 || end > len(runes) || string(runes[start:end]) != err.Token {
+		return text
+	}
+	return string(runes[:start]) + "«" + err.Token + "»" + string(runes[end:])
+}
@@ -0,0 +1,100 @@
+package entry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"15", []int{15}},
+		{"15+15+10", []int{15, 15, 10}},
+		{"15 + 15 + 10", []int{15, 15, 10}},
+		{"3x20", []int{20, 20, 20}},
+		{"3х20", []int{20, 20, 20}},
+		{"3×20", []int{20, 20, 20}},
+		{"3*20", []int{20, 20, 20}},
+		{"3 x 20", []int{20, 20, 20}},
+		{"2x10+5", []int{10, 10, 5}},
+		{"15 20 10", []int{15, 20, 10}},
+		{"15, 20; 10", []int{15, 20, 10}},
+		{"20,", []int{20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			parts, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.text, err)
+			}
+			if got := Counts(parts); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text   string
+		kind   ErrorKind
+		token  string
+		offset int
+	}{
+		{"15+abc", ErrorUnexpected, "abc", 3},
+		{"15 + ?", ErrorUnexpected, "?", 5},
+		{"привет, 15", ErrorUnexpected, "привет", 0},
+		{"3x", ErrorMissingNumber, "x", 1},
+		{"x20", ErrorMissingNumber, "x", 0},
+		{"0x20", ErrorUnexpected, "0x20", 0},
+		{"51x1", ErrorTooManyApproaches, "51x1", 0},
+		{"99999999999999999999", ErrorNumberTooLarge, "99999999999999999999", 0},
+		// Цифры не из ASCII не разбираются
+		{"10+１５", ErrorUnexpected, "１", 3},
+		{"١٥", ErrorUnexpected, "١", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			var entryErr *Error
+			if !errors.As(err, &entryErr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.text, err)
+			}
+			if entryErr.Kind != tt.kind || entryErr.Token != tt.token || entryErr.Offset != tt.offset {
+				t.Errorf("Parse(%q) error = %+v, want kind %d token %q at %d", tt.text, *entryErr, tt.kind, tt.token, tt.offset)
+			}
+		})
+	}
+}
+
+func TestMultiplied(t *testing.T) {
+	parts, err := Parse("3x20 3x20 15 20x3")
+	if err != nil {
+		t.Fatalf("Parse error = %v", err)
+	}
+
+	var got []string
+	for _, part := range Multiplied(parts) {
+		got = append(got, fmt.Sprintf("%s=%dx%d", part.Text, part.Sets, part.Count))
+	}
+	if want := "[3x20=3x20 3x20=3x20 20x3=20x3]"; fmt.Sprint(got) != want {
+		t.Errorf("Multiplied = %v, want %v", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	text := "15+двадцать+abc"
+	_, err := Parse(text)
+	var entryErr *Error
+	if !errors.As(err, &entryErr) {
+		t.Fatalf("Parse(%q) error = %v, want *Error", text, err)
+	}
+	if got, want := Highlight(text, entryErr), "15+двадцать+«abc»"; got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+}
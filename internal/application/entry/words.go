@@ -0,0 +1,265 @@
+package entry
+
+import (
+	"strings"
+)
+
+// wordKind место слова в составном числе
+type wordKind int
+
+const (
+	wordUnit    wordKind = iota // один..девять
+	wordTeen                    // десять..девятнадцать
+	wordTen                     // двадцать..девяносто
+	wordHundred                 // сто..девятьсот
+	wordScale                   // умножает число перед собой: hundred, тысяча, десяток, дюжина
+)
+
+// rank порядок разряда: слово продолжает число, только если его разряд младше предыдущего.
+// "двадцать пять" — одно число, "пять двадцать" — два подхода
+var rank = map[wordKind]int{
+	wordUnit:    1,
+	wordTeen:    1,
+	wordTen:     2,
+	wordHundred: 3,
+}
+
+type numberWord struct {
+	value int
+	kind  wordKind
+}
+
+// numberWords числительные, которыми пользователи диктуют подходы голосовым вводом.
+// Ключи в нижнем регистре, ё заменена на е
+var numberWords = map[string]numberWord{
+	"ноль": {0, wordUnit}, "один": {1, wordUnit}, "одна": {1, wordUnit}, "одно": {1, wordUnit},
+	"два": {2, wordUnit}, "две": {2, wordUnit}, "три": {3, wordUnit}, "четыре": {4, wordUnit},
+	"пять": {5, wordUnit}, "шесть": {6, wordUnit}, "семь": {7, wordUnit}, "восемь": {8, wordUnit},
+	"девять": {9, wordUnit},
+
+	"десять": {10, wordTeen}, "одиннадцать": {11, wordTeen}, "двенадцать": {12, wordTeen},
+	"тринадцать": {13, wordTeen}, "четырнадцать": {14, wordTeen}, "пятнадцать": {15, wordTeen},
+	"шестнадцать": {16, wordTeen}, "семнадцать": {17, wordTeen}, "восемнадцать": {18, wordTeen},
+	"девятнадцать": {19, wordTeen},
+
+	"двадцать": {20, wordTen}, "тридцать": {30, wordTen}, "сорок": {40, wordTen},
+	"пятьдесят": {50, wordTen}, "шестьдесят": {60, wordTen}, "семьдесят": {70, wordTen},
+	"восемьдесят": {80, wordTen}, "девяносто": {90, wordTen},
+
+	"сто": {100, wordHundred}, "двести": {200, wordHundred}, "триста": {300, wordHundred},
+	"четыреста": {400, wordHundred}, "пятьсот": {500, wordHundred}, "шестьсот": {600, wordHundred},
+	"семьсот": {700, wordHundred}, "восемьсот": {800, wordHundred}, "девятьсот": {900, wordHundred},
+
+	"десяток": {10, wordScale}, "десятка": {10, wordScale}, "десятков": {10, wordScale},
+	"дюжина": {12, wordScale}, "дюжины": {12, wordScale}, "дюжин": {12, wordScale},
+	"сотня": {100, wordScale}, "сотни": {100, wordScale}, "сотен": {100, wordScale},
+	"тысяча": {1000, wordScale}, "тысячи": {1000, wordScale}, "тысяч": {1000, wordScale},
+
+	"zero": {0, wordUnit}, "one": {1, wordUnit}, "two": {2, wordUnit}, "three": {3, wordUnit},
+	"four": {4, wordUnit}, "five": {5, wordUnit}, "six": {6, wordUnit}, "seven": {7, wordUnit},
+	"eight": {8, wordUnit}, "nine": {9, wordUnit},
+
+	"ten": {10, wordTeen}, "eleven": {11, wordTeen}, "twelve": {12, wordTeen},
+	"thirteen": {13, wordTeen}, "fourteen": {14, wordTeen}, "fifteen": {15, wordTeen},
+	"sixteen": {16, wordTeen}, "seventeen": {17, wordTeen}, "eighteen": {18, wordTeen},
+	"nineteen": {19, wordTeen},
+
+	"twenty": {20, wordTen}, "thirty": {30, wordTen}, "forty": {40, wordTen}, "fifty": {50, wordTen},
+	"sixty": {60, wordTen}, "seventy": {70, wordTen}, "eighty": {80, wordTen}, "ninety": {90, wordTen},
+
+	"hundred": {100, wordScale}, "hundreds": {100, wordScale}, "dozen": {12, wordScale},
+	"thousand": {1000, wordScale},
+}
+
+// connectors слова, которые могут стоять внутри числа: "one hundred and five"
+var connectors = map[string]bool{"and": true}
+
+// lookupNumberWord находит числительное. Опечатки исправляются, если слово однозначно
+// ближе всего к одному числительному: "питнадцать", "двацать", "fourty"
+func lookupNumberWord(word string) (numberWord, bool) {
+	word = normalizeWord(word)
+	if w, ok := numberWords[word]; ok {
+		return w, true
+	}
+
+	maxDistance := typoDistance(word)
+	if maxDistance == 0 {
+		return numberWord{}, false
+	}
+
+	var best numberWord
+	bestDistance, ambiguous := maxDistance+1, false
+	for candidate, w := range numberWords {
+		// Ограничение на единицу больше лучшего, чтобы точно видеть равные расстояния
+		d := levenshtein(word, candidate, bestDistance+1)
+		switch {
+		case d < bestDistance:
+			best, bestDistance, ambiguous = w, d, false
+		case d == bestDistance && w != best:
+			ambiguous = true
+		}
+	}
+	if bestDistance > maxDistance || ambiguous {
+		return numberWord{}, false
+	}
+
+	return best, true
+}
+
+// typoDistance допустимое число опечаток для слова. Короткие слова не исправляем:
+// "что" не должно стать "сто"
+func typoDistance(word string) int {
+	switch n := len([]rune(word)); {
+	case n >= 9:
+		return 2
+	case n >= 5:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func normalizeWord(word string) string {
+	return strings.ReplaceAll(strings.ToLower(word), "ё", "е")
+}
+
+// isNumberWord сообщает, является ли слово числительным или его опечаткой
+func isNumberWord(word string) bool {
+	_, ok := lookupNumberWord(word)
+	return ok
+}
+
+// combineWords заменяет последовательности числительных на числа: "двадцать пять" → 25,
+// "2 десятка" → 20, "one hundred and five" → 105. Остальные токены не меняются
+func combineWords(tokens []token) []token {
+	var result []token
+	for i := 0; i < len(tokens); {
+		value, n := composeNumber(tokens[i:])
+		if n == 0 {
+			tok := tokens[i]
+			if tok.kind == tokenWord {
+				tok.kind = tokenOther
+			}
+			result = append(result, tok)
+			i++
+			continue
+		}
+
+		first, last := tokens[i], tokens[i+n-1]
+		result = append(result, token{
+			kind:   tokenNumber,
+			text:   string(first.source[first.offset:last.end()]),
+			source: first.source,
+			value:  value,
+			offset: first.offset,
+		})
+		i += n
+	}
+	return result
+}
+
+// composeNumber собирает число из начала tokens и возвращает его значение и число
+// использованных токенов. n == 0 — tokens не начинается с числительного
+func composeNumber(tokens []token) (value, n int) {
+	total, group, last := 0, 0, 0 // last — разряд последнего слова в group, 0 — group пуст
+	words := 0
+
+	for n < len(tokens) {
+		tok := tokens[n]
+
+		// Число цифрами начинает смешанную форму "2 десятка", дальше может идти только множитель
+		if tok.kind == tokenNumber {
+			if n > 0 {
+				break
+			}
+			if n+1 >= len(tokens) || tokens[n+1].kind != tokenWord {
+				return 0, 0
+			}
+			if w, ok := lookupNumberWord(tokens[n+1].text); !ok || w.kind != wordScale {
+				return 0, 0
+			}
+			group, last = tok.value, rank[wordUnit]
+			n++
+			continue
+		}
+		if tok.kind != tokenWord {
+			break
+		}
+
+		if connectors[normalizeWord(tok.text)] {
+			// "and" остается внутри числа, только если за ним идет младший разряд
+			if last != rank[wordHundred] || n+1 >= len(tokens) || tokens[n+1].kind != tokenWord {
+				break
+			}
+			if w, ok := lookupNumberWord(tokens[n+1].text); !ok || w.kind == wordScale || w.kind == wordHundred {
+				break
+			}
+			n++
+			continue
+		}
+
+		w, ok := lookupNumberWord(tok.text)
+		if !ok {
+			break
+		}
+
+		if w.kind == wordScale {
+			if group == 0 && last == 0 {
+				group = 1 // "десяток", "hundred" без числа перед ними
+			}
+			if w.value >= 1000 {
+				total += group * w.value
+				group, last = 0, rank[wordHundred]+1
+			} else {
+				group *= w.value
+				last = rank[wordHundred]
+			}
+		} else {
+			if last != 0 && rank[w.kind] >= last {
+				break // младший разряд уже занят: начинается следующее число
+			}
+			group += w.value
+			last = rank[w.kind]
+		}
+		words++
+		n++
+	}
+
+	if words == 0 {
+		return 0, 0
+	}
+	return total + group, n
+}
+
+// levenshtein расстояние редактирования между строками в символах. Вычисление прекращается,
+// когда расстояние заведомо не меньше limit
+func levenshtein(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d >= limit || -d >= limit {
+		return limit
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin >= limit {
+			return limit
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
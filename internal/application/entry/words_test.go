@@ -0,0 +1,101 @@
+package entry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseNumberWords(t *testing.T) {
+	tests := []struct {
+		text string
+		want []int
+	}{
+		{"двадцать пять", []int{25}},
+		{"Двадцать пять", []int{25}},
+		{"сто двадцать пять", []int{125}},
+		{"пять двадцать", []int{5, 20}},
+		{"двадцать и двадцать", []int{20, 20}},
+		{"пятнадцать плюс десять", []int{15, 10}},
+		{"2 десятка", []int{20}},
+		{"десяток", []int{10}},
+		{"полторы", nil},
+		{"три по двадцать", []int{20, 20, 20}},
+		{"3x двадцать", []int{20, 20, 20}},
+		{"тысяча", []int{1000}},
+		{"fifteen", []int{15}},
+		{"twenty-five", []int{25}},
+		{"one hundred and five", []int{105}},
+		{"one hundred and five plus ten", []int{105, 10}},
+		{"a dozen", nil},
+		// Опечатки в длинных числительных
+		{"питнадцать", []int{15}},
+		{"двацать", []int{20}},
+		{"fourty", []int{40}},
+		{"пятнацать пять", []int{15, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			parts, err := Parse(tt.text)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %v, want error", tt.text, Counts(parts))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.text, err)
+			}
+			if got := Counts(parts); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNumberWordErrors(t *testing.T) {
+	tests := []struct {
+		text   string
+		token  string
+		offset int
+	}{
+		// Короткие слова не исправляются: "что" не становится "сто"
+		{"что", "что", 0},
+		{"двадцать пять штук", "штук", 14},
+		{"пятнадцать+сорк", "сорк", 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			var entryErr *Error
+			if !errors.As(err, &entryErr) {
+				t.Fatalf("Parse(%q) error = %v, want *Error", tt.text, err)
+			}
+			if entryErr.Kind != ErrorUnexpected || entryErr.Token != tt.token || entryErr.Offset != tt.offset {
+				t.Errorf("Parse(%q) error = %+v, want token %q at %d", tt.text, *entryErr, tt.token, tt.offset)
+			}
+		})
+	}
+}
+
+func TestHasNumber(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"15", true},
+		{"двадцать пять", true},
+		{"питнадцать", true},
+		{"привет", false},
+		{"что нового", false},
+		{"１５", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasNumber(tt.text); got != tt.want {
+			t.Errorf("HasNumber(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Данные inline кнопок подтверждения рассылки
+const (
+	broadcastConfirmCommand = "/broadcast_confirm"
+	broadcastCancelCommand  = "/broadcast_cancel"
+)
+
+// AdminHandler обрабатывает команды администраторов
+type AdminHandler struct {
+	adminService  *services.AdminService
+	pushupService *services.PushupService
+	router        *router.Router
+	commands      []router.Command // команды для справки /admin
+	logger        *zerolog.Logger
+}
+
+// NewAdminHandler создает обработчик административных команд и регистрирует их в роутере
+func NewAdminHandler(
+	adminService *services.AdminService,
+	pushupService *services.PushupService,
+	r *router.Router,
+	logger *zerolog.Logger,
+) *AdminHandler {
+	h := &AdminHandler{
+		adminService:  adminService,
+		pushupService: pushupService,
+		router:        r,
+		logger:        logger,
+	}
+	h.register()
+
+	return h
+}
+
+// register добавляет административные команды. Они скрыты из общей справки, меню и клавиатуры,
+// а их выполнение проверяет middleware Authorize
+func (h *AdminHandler) register() {
+	h.add(router.Command{
+		Names:       []string{"/admin"},
+		Description: "— список команд администратора",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleAdmin(ctx)
+		},
+	})
+	h.add(router.Command{
+		Names:       []string{"/admin_stats"},
+		Description: "— пользователи и активность за сегодня",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleStats(ctx)
+		},
+	})
+	h.add(router.Command{
+		Names:       []string{"/broadcast"},
+		Description: "<текст> — рассылка всем пользователям с подтверждением",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleBroadcast(ctx, req.User, req.Args)
+		},
+	})
+	h.add(router.Command{
+		Names:       []string{"/ban"},
+		Description: "<id> — заблокировать пользователя",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleBan(ctx, req.User, req.Args)
+		},
+	})
+	h.add(router.Command{
+		Names:       []string{"/unban"},
+		Description: "<id> — разблокировать пользователя",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleUnban(ctx, req.User, req.Args)
+		},
+	})
+	h.add(router.Command{
+		Names:       []string{"/user"},
+		Description: "<id> — данные пользователя",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleUser(ctx, req.Args)
+		},
+	})
+
+	// Кнопки под предпросмотром рассылки, в справку не попадают
+	h.router.Register(router.Command{
+		Names:      []string{broadcastConfirmCommand},
+		Permission: router.PermissionAdmin,
+		Hidden:     true,
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleBroadcastConfirm(ctx, req.User)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:      []string{broadcastCancelCommand},
+		Permission: router.PermissionAdmin,
+		Hidden:     true,
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleBroadcastCancel(ctx, req.User)
+		},
+	})
+}
+
+// add регистрирует скрытую команду, доступную только администраторам
+func (h *AdminHandler) add(cmd router.Command) {
+	cmd.Permission = router.PermissionAdmin
+	cmd.Hidden = true
+	h.router.Register(cmd)
+	h.commands = append(h.commands, cmd)
+}
+
+// HandleAdmin обрабатывает команду /admin
+func (h *AdminHandler) HandleAdmin(ctx context.Context) *response.Response {
+	var b strings.Builder
+	b.WriteString("🛠 Команды администратора:\n")
+	for _, cmd := range h.commands {
+		fmt.Fprintf(&b, "%s %s\n", cmd.Names[0], cmd.Description)
+	}
+
+	return response.Message(b.String())
+}
+
+// HandleStats обрабатывает команду /admin_stats
+func (h *AdminHandler) HandleStats(ctx context.Context) *response.Response {
+	stats, err := h.adminService.GetStats(ctx)
+	if err != nil {
+		return response.Message("Ошибка при получении статистики. Попробуйте позже.")
+	}
+
+	text := "📊 Статистика бота:\n\n"
+	text += fmt.Sprintf("Пользователей: %d\n", stats.Users)
+	text += fmt.Sprintf("Заблокировано: %d\n", stats.BannedUsers)
+	text += fmt.Sprintf("Недоступны (заблокировали бота): %d\n", stats.InactiveUsers)
+	text += fmt.Sprintf("Активных сегодня: %d\n", stats.ActiveToday)
+	text += fmt.Sprintf("Подходов сегодня: %d\n", stats.ApproachesToday)
+	text += fmt.Sprintf("Отжиманий сегодня: %d\n", stats.PushupsToday)
+
+	return response.Message(text)
+}
+
+// HandleBroadcast обрабатывает команду /broadcast: показывает предпросмотр и кнопки подтверждения
+func (h *AdminHandler) HandleBroadcast(ctx context.Context, admin *models.User, text string) *response.Response {
+	if text == "" {
+		return response.Message("Использование: /broadcast <текст сообщения>")
+	}
+
+	h.adminService.PrepareBroadcast(admin.TelegramID, text)
+
+	preview := fmt.Sprintf("📣 Предпросмотр рассылки:\n\n%s\n\nОтправить всем пользователям?", text)
+	keyboard := response.InlineKeyboard{Rows: [][]response.InlineButton{{
+		{Text: "✅ Отправить", Data: broadcastConfirmCommand},
+		{Text: "❌ Отмена", Data: broadcastCancelCommand},
+	}}}
+
+	return response.MessageWithKeyboard(preview, keyboard)
+}
+
+// HandleBroadcastConfirm отправляет подготовленную рассылку
+func (h *AdminHandler) HandleBroadcastConfirm(ctx context.Context, admin *models.User) *response.Response {
+	text, recipients, err := h.adminService.ConfirmBroadcast(ctx, admin.TelegramID)
+	if err == errors.ErrNoPendingBroadcast {
+		return response.New(response.CallbackAnswer{Text: "Нет рассылки, ожидающей подтверждения"})
+	}
+	if err != nil {
+		return response.New(response.CallbackAnswer{Text: "Ошибка при отправке рассылки", ShowAlert: true})
+	}
+
+	resp := response.New(
+		response.Edit{Text: fmt.Sprintf("📣 Рассылка отправлена, получателей: %d\n\n%s", len(recipients), text)},
+		response.CallbackAnswer{Text: "Рассылка отправлена"},
+	)
+	for _, chatID := range recipients {
+		resp.Add(response.Text{Text: text, ChatID: chatID})
+	}
+
+	return resp
+}
+
+// HandleBroadcastCancel отменяет подготовленную рассылку
+func (h *AdminHandler) HandleBroadcastCancel(ctx context.Context, admin *models.User) *response.Response {
+	if !h.adminService.CancelBroadcast(admin.TelegramID) {
+		return response.New(response.CallbackAnswer{Text: "Нет рассылки, ожидающей подтверждения"})
+	}
+
+	return response.New(
+		response.Edit{Text: "❌ Рассылка отменена"},
+		response.CallbackAnswer{},
+	)
+}
+
+// HandleBan обрабатывает команду /ban <id>
+func (h *AdminHandler) HandleBan(ctx context.Context, admin *models.User, args string) *response.Response {
+	telegramID, ok := parseUserID(args)
+	if !ok {
+		return response.Message("Использование: /ban <id пользователя>")
+	}
+
+	user, err := h.adminService.Ban(ctx, admin.TelegramID, telegramID)
+	switch err {
+	case nil:
+		return response.Message(fmt.Sprintf("⛔ Пользователь %s (%d) заблокирован.", user.NickName, user.TelegramID))
+	case errors.ErrUserNotFound:
+		return response.Message(fmt.Sprintf("Пользователь %d не найден.", telegramID))
+	case errors.ErrCannotBanAdmin:
+		return response.Message("Нельзя заблокировать администратора.")
+	default:
+		return response.Message("Ошибка при блокировке пользователя. Попробуйте позже.")
+	}
+}
+
+// HandleUnban обрабатывает команду /unban <id>
+func (h *AdminHandler) HandleUnban(ctx context.Context, admin *models.User, args string) *response.Response {
+	telegramID, ok := parseUserID(args)
+	if !ok {
+		return response.Message("Использование: /unban <id пользователя>")
+	}
+
+	user, err := h.adminService.Unban(ctx, admin.TelegramID, telegramID)
+	switch err {
+	case nil:
+		return response.Message(fmt.Sprintf("✅ Пользователь %s (%d) разблокирован.", user.NickName, user.TelegramID))
+	case errors.ErrUserNotFound:
+		return response.Message(fmt.Sprintf("Пользователь %d не найден.", telegramID))
+	default:
+		return response.Message("Ошибка при разблокировке пользователя. Попробуйте позже.")
+	}
+}
+
+// HandleUser обрабатывает команду /user <id>: показывает профиль и статистику пользователя
+func (h *AdminHandler) HandleUser(ctx context.Context, args string) *response.Response {
+	telegramID, ok := parseUserID(args)
+	if !ok {
+		return response.Message("Использование: /user <id пользователя>")
+	}
+
+	user, err := h.adminService.GetUser(ctx, telegramID)
+	if err == errors.ErrUserNotFound {
+		return response.Message(fmt.Sprintf("Пользователь %d не найден.", telegramID))
+	}
+	if err != nil {
+		return response.Message("Ошибка при получении пользователя. Попробуйте позже.")
+	}
+
+	status := "активен"
+	switch {
+	case user.IsBanned():
+		status = "заблокирован " + user.BannedAt.Format("02.01.2006 15:04")
+	case user.IsInactive():
+		status = "недоступен с " + user.InactiveAt.Format("02.01.2006 15:04") + " (заблокировал бота или удалил аккаунт)"
+	}
+
+	text := "👤 Пользователь:\n\n"
+	text += fmt.Sprintf("ID: %d\n", user.TelegramID)
+	text += fmt.Sprintf("Имя: %s\n", user.NickName)
+	text += fmt.Sprintf("Username: %s\n", user.Phone)
+	text += fmt.Sprintf("Роль: %s\n", user.Role)
+	text += fmt.Sprintf("Статус: %s\n", status)
+
+	// Ошибки статистики записывает в лог сервис, профиль показываем без нее
+	today, err := h.pushupService.GetTodayStats(ctx, user)
+	if err == nil && today != nil {
+		text += fmt.Sprintf("\nСегодня: %d отжиманий, подходов: %d\n", today.GetTotalCount(), today.GetApproachCount())
+	}
+	weekly, err := h.pushupService.GetWeeklyStats(ctx, user)
+	if err == nil {
+		text += fmt.Sprintf("За неделю: %d отжиманий, дней: %d\n", weekly.TotalCount, weekly.TrainingDays)
+	}
+	monthly, err := h.pushupService.GetMonthlyStats(ctx, user)
+	if err == nil {
+		text += fmt.Sprintf("За месяц: %d отжиманий, дней: %d\n", monthly.TotalCount, monthly.TrainingDays)
+	}
+
+	return response.Message(text)
+}
+
+// parseUserID разбирает Telegram ID из аргументов команды
+func parseUserID(args string) (int64, bool) {
+	id, err := strconv.ParseInt(strings.TrimSpace(args), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -1,129 +1,375 @@
 package handlers
 
 import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
 	"azhumania/internal/application/services"
+	"azhumania/internal/domain/errors"
 	"azhumania/internal/domain/models"
+	"azhumania/internal/logging"
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog"
 )
 
 // CommandHandler обрабатывает команды пользователей
 type CommandHandler struct {
-	userService   *services.UserService
-	pushupService *services.PushupService
-	logger        *zerolog.Logger
+	userService    *services.UserService
+	pushupService  *services.PushupService
+	tokenService   *services.TokenService
+	programService *services.ProgramService
+	router         *router.Router
+	logger         *zerolog.Logger
 }
 
-// NewCommandHandler создает новый обработчик команд
-func NewCommandHandler(userService *services.UserService, pushupService *services.PushupService, logger *zerolog.Logger) *CommandHandler {
-	return &CommandHandler{
-		userService:   userService,
-		pushupService: pushupService,
-		logger:        logger,
+// NewCommandHandler создает новый обработчик команд и регистрирует команды в роутере
+func NewCommandHandler(
+	userService *services.UserService,
+	pushupService *services.PushupService,
+	tokenService *services.TokenService,
+	programService *services.ProgramService,
+	r *router.Router,
+	logger *zerolog.Logger,
+) *CommandHandler {
+	h := &CommandHandler{
+		userService:    userService,
+		pushupService:  pushupService,
+		tokenService:   tokenService,
+		programService: programService,
+		router:         r,
+		logger:         logger,
 	}
+	h.register()
+
+	return h
+}
+
+// register добавляет команды в роутер. Справка, клавиатура и меню команд Telegram
+// строятся из этого списка
+func (h *CommandHandler) register() {
+	h.router.Register(router.Command{
+		Names:       []string{"/start"},
+		Buttons:     []string{"🏠 Главное меню"},
+		Description: "приветствие и инструкции",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleStart(ctx, req.User)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/stats"},
+		Buttons:     []string{"📊 Статистика"},
+		Description: "статистика за неделю или период: /stats 30d, /stats 01.10 15.10",
+		Expensive:   true,
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleStats(ctx, req.User, req.Args)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/records"},
+		Buttons:     []string{"🏆 Рекорды"},
+		Description: "личные рекорды",
+		Expensive:   true,
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleRecords(ctx, req.User)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/goal"},
+		Description: "цель отжиманий на день",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleGoal(ctx, req.User, req.Args)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/weekstart"},
+		Description: "день начала недели в статистике",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleWeekStart(ctx, req.User, req.Args)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/help"},
+		Buttons:     []string{"❓ Помощь"},
+		Description: "список команд и подсказки",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleHelp(ctx, req.User)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/token"},
+		Description: "токен для REST API",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			if req.Message.Chat != nil && !req.Message.Chat.IsPrivate() {
+				return response.Message("🔒 Токен можно получить только в личном чате с ботом.")
+			}
+			return h.HandleToken(ctx, req.User)
+		},
+	})
+	h.router.NotFound(func(ctx context.Context, req *router.Request) *response.Response {
+		return h.HandleUnknownCommand(ctx, req.Text)
+	})
 }
 
 // HandleStart обрабатывает команду /start
-func (h *CommandHandler) HandleStart(ctx context.Context, user *models.User) (string, interface{}) {
+func (h *CommandHandler) HandleStart(ctx context.Context, user *models.User) *response.Response {
 	message := fmt.Sprintf(`Привет, %s! 👋
 
 Я помогу тебе отслеживать твои отжимания.
 
-📝 Как использовать:
-• Просто отправляй количество отжиманий в каждом подходе
-• Например: "15", "20", "10"
-
-📊 Команды:
-/start - это сообщение
-/stats - статистика за неделю
-/help - помощь
-
-💡 Советы:
-• Делайте перерывы между подходами
-• Постепенно увеличивайте нагрузку
-• Регулярность важнее количества
+`, user.NickName)
 
-Удачи в тренировках! 💪`, user.NickName)
-
-	// Создаем клавиатуру с кнопками
-	keyboard := tgbotapi.ReplyKeyboardMarkup{
-		Keyboard: [][]tgbotapi.KeyboardButton{
-			{
-				tgbotapi.KeyboardButton{Text: "📊 Статистика"},
-				tgbotapi.KeyboardButton{Text: "❓ Помощь"},
-			},
-			{
-				tgbotapi.KeyboardButton{Text: "🏠 Главное меню"},
-			},
-		},
-		ResizeKeyboard:  true,
-		OneTimeKeyboard: false,
-		Selective:       false,
+	// План программы на сегодня; ошибку записывает в лог сервис, приветствие показываем без плана
+	if status, err := h.programService.Today(ctx, user); err == nil && status != nil {
+		message += formatProgramStatus(status) + "\n\n"
 	}
+	message += h.usage()
 
-	return message, keyboard
+	return response.MessageWithKeyboard(message, h.router.Keyboard())
 }
 
 // HandleHelp обрабатывает команду /help
-func (h *CommandHandler) HandleHelp(ctx context.Context, user *models.User) (string, interface{}) {
-	message := `🤖 Помощь по использованию бота:
+func (h *CommandHandler) HandleHelp(ctx context.Context, user *models.User) *response.Response {
+	return response.Message("🤖 Помощь по использованию бота:\n\n" + h.usage())
+}
 
-📝 Как использовать:
+// usage возвращает инструкцию и список команд, общие для /start и /help
+func (h *CommandHandler) usage() string {
+	return fmt.Sprintf(`📝 Как использовать:
 • Просто отправляй количество отжиманий в каждом подходе
 • Например: "15", "20", "10"
+• Несколько подходов сразу: "15+15+10", "3x20", "15 20 10"
+• Можно словами: "двадцать пять", "2 десятка"
 
 📊 Команды:
-/start - приветствие и инструкции
-/stats - статистика за неделю
-/help - это сообщение
+%s
 
 💡 Советы:
-• Делайте перерывы между подходами
+• Делайте перерывы между подходами — /timer напомнит, когда отдых закончится
 • Постепенно увеличивайте нагрузку
 • Регулярность важнее количества
 
-Удачи в тренировках! 💪`
+Удачи в тренировках! 💪`, h.router.HelpText())
+}
+
+// HandleStats обрабатывает команду /stats [<период> | <с> [<по>]]. Без аргументов показывает
+// текущую неделю с дня начала недели пользователя
+func (h *CommandHandler) HandleStats(ctx context.Context, user *models.User, args string) *response.Response {
+	// Периоды считаются от сегодняшнего дня пользователя, как неделя в DigestService.Current
+	today := user.Today()
+	arg := strings.ToLower(strings.TrimSpace(args))
+	if arg == "" {
+		arg = statsPresets[0].arg
+	}
+
+	var period models.Period
+	title := ""
+	if preset, ok := findStatsPreset(arg); ok {
+		period, title = preset.period(today, user), preset.title
+	} else if period, ok = parsePeriod(arg, today); !ok {
+		return response.MessageWithKeyboard("Укажите период: /stats 7d, /stats month, /stats 01.10 15.10 "+
+			"или одну дату, с которой считать до сегодня: /stats 01.09", statsKeyboard())
+	}
+
+	stats, err := h.pushupService.GetStats(ctx, user.ID, period)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to get period stats")
+		return response.Message("Ошибка при получении статистики. Попробуйте позже.")
+	}
+
+	header := "📈 " + formatPeriod(stats.Period)
+	if title != "" {
+		header = fmt.Sprintf("📈 %s (%s)", title, formatPeriod(stats.Period))
+	}
+	if stats.TotalCount == 0 {
+		return response.MessageWithKeyboard(header+"\n\nЗа этот период отжиманий нет. "+
+			"Начните тренировки, отправляя количество отжиманий!", statsKeyboard())
+	}
+
+	var text strings.Builder
+	text.WriteString(header + "\n\n")
+	fmt.Fprintf(&text, "Всего отжиманий: %d\n", stats.TotalCount)
+	fmt.Fprintf(&text, "Подходов: %d, лучший подход: %d\n", stats.Approaches, stats.BestApproach)
+	fmt.Fprintf(&text, "Дней тренировок: %d из %d\n", stats.TrainingDays, stats.Period.Days())
+	fmt.Fprintf(&text, "Среднее в день тренировки: %.1f\n", stats.AveragePerDay)
+	fmt.Fprintf(&text, "Среднее в день периода: %.1f\n", stats.AveragePerPeriodDay())
+	fmt.Fprintf(&text, "Лучший день: %d отжиманий (%s)\n", stats.BestDay, stats.BestDayDate.Format("02.01.2006"))
 
-	return message, nil
+	// Мотивация только для текущей недели: пороги рассчитаны на неделю
+	if title == statsPresets[0].title {
+		switch {
+		case stats.TotalCount > 200:
+			text.WriteString("\n🔥 Отличная неделя! Вы на правильном пути!")
+		case stats.TotalCount > 100:
+			text.WriteString("\n💪 Хорошая работа! Можете больше!")
+		default:
+			text.WriteString("\n👍 Начинаем! Каждый день важен!")
+		}
+	}
+
+	return response.MessageWithKeyboard(text.String(), statsKeyboard())
 }
 
-// HandleStats обрабатывает команду /stats
-func (h *CommandHandler) HandleStats(ctx context.Context, user *models.User) (string, interface{}) {
-	weeklyStats, err := h.pushupService.GetWeeklyStats(ctx, user.ID)
+// statsKeyboard возвращает кнопки готовых периодов /stats
+func statsKeyboard() response.InlineKeyboard {
+	buttons := make([]response.InlineButton, len(statsPresets))
+	for i, preset := range statsPresets {
+		buttons[i] = response.InlineButton{Text: preset.title, Data: "/stats " + preset.arg}
+	}
+	return response.InlineKeyboard{Rows: [][]response.InlineButton{buttons[:4], buttons[4:6], buttons[6:]}}
+}
+
+// formatPeriod форматирует период как «01.10.2026 – 15.10.2026» или одну дату
+func formatPeriod(period models.Period) string {
+	if period.From.Equal(period.To) {
+		return period.From.Format("02.01.2006")
+	}
+	return period.From.Format("02.01.2006") + " – " + period.To.Format("02.01.2006")
+}
+
+// HandleRecords обрабатывает команду /records
+func (h *CommandHandler) HandleRecords(ctx context.Context, user *models.User) *response.Response {
+	records, err := h.pushupService.GetRecords(ctx, user)
 	if err != nil {
-		h.logger.Error().Err(err).Int64("userID", user.ID).Msg("failed to get weekly stats")
-		return "Ошибка при получении статистики. Попробуйте позже.", nil
+		return response.Message("Ошибка при получении рекордов. Попробуйте позже.")
+	}
+
+	if len(records.Records) == 0 {
+		return response.Message("🏆 У вас пока нет рекордов.\n\nНачните тренировки, отправляя количество отжиманий!")
+	}
+
+	text := "🏆 Личные рекорды:\n\n"
+	for _, kind := range models.RecordKinds {
+		record, ok := records.Records[kind]
+		if !ok {
+			continue
+		}
+		text += fmt.Sprintf("%s: %s — %s\n", recordTitle(kind), formatRecordValue(record), formatRecordDate(record, user.WeekStart))
+	}
+
+	return response.Message(text)
+}
+
+// recordTitle возвращает название рекорда
+func recordTitle(kind models.RecordKind) string {
+	switch kind {
+	case models.RecordMaxSet:
+		return "Лучший подход"
+	case models.RecordMaxDay:
+		return "Лучший день"
+	case models.RecordMaxWeek:
+		return "Лучшая неделя"
+	case models.RecordLongestStreak:
+		return "Самая длинная серия"
+	default:
+		return string(kind)
 	}
+}
 
-	if weeklyStats.TotalCount == 0 {
-		return "📈 У вас пока нет статистики за неделю.\n\nНачните тренировки, отправляя количество отжиманий!", nil
+// formatRecordValue форматирует значение рекорда с единицей измерения
+func formatRecordValue(record models.PersonalRecord) string {
+	if record.Kind == models.RecordLongestStreak {
+		return fmt.Sprintf("%d дн. подряд", record.Value)
 	}
+	return fmt.Sprintf("%d отжиманий", record.Value)
+}
 
-	response := "📈 Статистика за неделю:\n\n"
-	response += fmt.Sprintf("Всего отжиманий: %d\n", weeklyStats.TotalCount)
-	response += fmt.Sprintf("Дней тренировок: %d\n", weeklyStats.TrainingDays)
-	response += fmt.Sprintf("Среднее в день: %.1f\n", weeklyStats.AveragePerDay)
-	response += fmt.Sprintf("Лучший день: %d отжиманий\n", weeklyStats.BestDay)
+// formatRecordDate форматирует дату рекорда: для недели — ее первый день по настройке first,
+// для серии — последний день
+func formatRecordDate(record models.PersonalRecord, first time.Weekday) string {
+	switch record.Kind {
+	case models.RecordMaxWeek:
+		return "неделя с " + models.WeekOf(record.AchievedOn, first).From.Format("02.01.2006")
+	case models.RecordLongestStreak:
+		return "по " + record.AchievedOn.Format("02.01.2006")
+	default:
+		return record.AchievedOn.Format("02.01.2006")
+	}
+}
+
+// HandleGoal обрабатывает команду /goal [<отжиманий в день> | off]
+func (h *CommandHandler) HandleGoal(ctx context.Context, user *models.User, args string) *response.Response {
+	arg := strings.ToLower(strings.TrimSpace(args))
+	if arg == "" {
+		if user.DailyGoal == 0 {
+			return response.Message("🎯 Дневная цель не задана. Задать: /goal 50")
+		}
+		return response.Message(fmt.Sprintf("🎯 Цель: %d отжиманий в день. Изменить: /goal 60, убрать: /goal off", user.DailyGoal))
+	}
 
-	// Добавляем мотивацию
-	if weeklyStats.TotalCount > 200 {
-		response += "\n🔥 Отличная неделя! Вы на правильном пути!"
-	} else if weeklyStats.TotalCount > 100 {
-		response += "\n💪 Хорошая работа! Можете больше!"
-	} else {
-		response += "\n👍 Начинаем! Каждый день важен!"
+	goal := 0
+	if !slices.Contains(switchOffArgs, arg) {
+		value, err := strconv.Atoi(arg)
+		if err != nil {
+			return response.Message("Укажите число отжиманий в день, например: /goal 50. Убрать цель: /goal off")
+		}
+		goal = value
 	}
 
-	return response, nil
+	err := h.userService.SetDailyGoal(ctx, user, goal)
+	switch {
+	case err == nil && goal == 0:
+		return response.Message("🎯 Дневная цель убрана.")
+	case err == nil:
+		return response.Message(fmt.Sprintf("🎯 Цель: %d отжиманий в день.", goal))
+	case err == errors.ErrInvalidDailyGoal:
+		return response.Message(fmt.Sprintf("Цель должна быть от 1 до %d отжиманий в день.", models.MaxDailyGoal))
+	default:
+		return response.Message("Ошибка при сохранении цели. Попробуйте позже.")
+	}
+}
+
+// HandleWeekStart обрабатывает команду /weekstart [<день недели>]
+func (h *CommandHandler) HandleWeekStart(ctx context.Context, user *models.User, args string) *response.Response {
+	keyboard := response.InlineKeyboard{Rows: [][]response.InlineButton{{
+		{Text: "Понедельник", Data: "/weekstart пн"},
+		{Text: "Воскресенье", Data: "/weekstart вс"},
+		{Text: "Суббота", Data: "/weekstart сб"},
+	}}}
+
+	arg := strings.ToLower(strings.TrimSpace(args))
+	if arg == "" {
+		return response.MessageWithKeyboard(fmt.Sprintf("📅 Неделя в статистике начинается с %s. Изменить: /weekstart вс",
+			weekdayGenitive[weekdayIndex(user.WeekStart)]), keyboard)
+	}
+
+	day, ok := weekdayArgs[arg]
+	if !ok {
+		return response.MessageWithKeyboard("Укажите день недели, например: /weekstart пн или /weekstart вс", keyboard)
+	}
+	if err := h.userService.SetWeekStart(ctx, user, day); err != nil {
+		return response.Message("Ошибка при сохранении начала недели. Попробуйте позже.")
+	}
+
+	return response.Message(fmt.Sprintf("📅 Теперь неделя в /stats, /records и сводке начинается с %s.", weekdayGenitive[weekdayIndex(day)]))
+}
+
+// HandleToken обрабатывает команду /token: выдает новый токен для REST API
+func (h *CommandHandler) HandleToken(ctx context.Context, user *models.User) *response.Response {
+	token, err := h.tokenService.IssueToken(ctx, user.ID)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to issue api token")
+		return response.Message("Ошибка при создании токена. Попробуйте позже.")
+	}
+
+	message := fmt.Sprintf(`🔑 Ваш токен для API:
+
+<code>%s</code>
+
+Передавайте его в заголовке Authorization: Bearer &lt;токен&gt;.
+Предыдущий токен больше не действует. Никому не показывайте этот токен.`, token)
+
+	return response.New(response.Text{Text: message, ParseMode: response.ParseModeHTML})
 }
 
 // HandleUnknownCommand обрабатывает неизвестные команды
-func (h *CommandHandler) HandleUnknownCommand(ctx context.Context, command string) (string, interface{}) {
+func (h *CommandHandler) HandleUnknownCommand(ctx context.Context, command string) *response.Response {
 	message := fmt.Sprintf(`Неизвестная команда: %s
 
 Отправь мне количество отжиманий (например: 15) или используй команду /help`, command)
-	return message, nil
+	return response.Message(message)
 }
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// dayLayouts форматы даты в аргументах команд: 19.10.2026, 2026-10-19 и 19.10 без года
+var dayLayouts = []string{"2.1.2006", "2006-01-02", "2.1"}
+
+// relativeDays дни, которые можно указать словом, со сдвигом от сегодня
+var relativeDays = map[string]int{
+	"сегодня":   0,
+	"today":     0,
+	"вчера":     -1,
+	"yesterday": -1,
+	"позавчера": -2,
+}
+
+// goalBarWidth длина полоски прогресса дневной цели
+const goalBarWidth = 10
+
+// DayHandler обрабатывает команды /today и /day: подходы за день по порядку
+type DayHandler struct {
+	pushupService *services.PushupService
+	router        *router.Router
+	logger        *zerolog.Logger
+}
+
+// NewDayHandler создает обработчик подходов за день и регистрирует его команды в роутере
+func NewDayHandler(pushupService *services.PushupService, r *router.Router, logger *zerolog.Logger) *DayHandler {
+	h := &DayHandler{
+		pushupService: pushupService,
+		router:        r,
+		logger:        logger,
+	}
+	h.register()
+
+	return h
+}
+
+func (h *DayHandler) register() {
+	h.router.Register(router.Command{
+		Names:       []string{"/today"},
+		Buttons:     []string{"📅 Сегодня"},
+		Description: "подходы за сегодня",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleToday(ctx, req.User)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/day"},
+		Description: "подходы за любой день",
+		Expensive:   true,
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleDay(ctx, req.User, req.Args)
+		},
+	})
+}
+
+// HandleToday обрабатывает команду /today
+func (h *DayHandler) HandleToday(ctx context.Context, user *models.User) *response.Response {
+	return h.showDay(ctx, user, user.Today())
+}
+
+// HandleDay обрабатывает команду /day <дата>
+func (h *DayHandler) HandleDay(ctx context.Context, user *models.User, args string) *response.Response {
+	const usage = "Укажите день: /day 12.10, /day 12.10.2025, /day 2025-10-12 или /day вчера"
+
+	today := user.Today()
+	if strings.TrimSpace(args) == "" {
+		return response.Message(usage)
+	}
+	day, ok := parseDay(args, today)
+	if !ok {
+		return response.Message("Не понял дату «" + strings.TrimSpace(args) + "». " + usage)
+	}
+	if day.After(today) {
+		return response.Message("Этот день еще не наступил. Подходы за сегодня: /today")
+	}
+
+	return h.showDay(ctx, user, day)
+}
+
+// showDay показывает подходы за день с кнопками перехода на соседние дни
+func (h *DayHandler) showDay(ctx context.Context, user *models.User, day time.Time) *response.Response {
+	session, err := h.pushupService.GetDayStats(ctx, user, day)
+	if err != nil {
+		return response.Message("Ошибка при получении подходов. Попробуйте позже.")
+	}
+
+	today := user.Today()
+	row := []response.InlineButton{{Text: "◀️ " + day.AddDate(0, 0, -1).Format("02.01"), Data: "/day " + day.AddDate(0, 0, -1).Format("2006-01-02")}}
+	if next := day.AddDate(0, 0, 1); !next.After(today) {
+		row = append(row, response.InlineButton{Text: next.Format("02.01") + " ▶️", Data: "/day " + next.Format("2006-01-02")})
+	}
+
+	return response.MessageWithKeyboard(formatDay(session, user, day.Equal(today)), response.InlineKeyboard{Rows: [][]response.InlineButton{row}})
+}
+
+// formatDay форматирует подходы за день: время и перерывы, итог и прогресс дневной цели
+func formatDay(session *models.PushupSession, user *models.User, isToday bool) string {
+	var text strings.Builder
+	if isToday {
+		fmt.Fprintf(&text, "📅 Сегодня, %s\n\n", session.Date.Format("02.01.2006"))
+	} else {
+		fmt.Fprintf(&text, "📅 %s, %s\n\n", weekdayNames[weekdayIndex(session.Date.Weekday())], session.Date.Format("02.01.2006"))
+	}
+
+	if len(session.Approaches) == 0 {
+		if isToday {
+			text.WriteString("Сегодня подходов еще не было. Отправьте число отжиманий, например: 15")
+		} else {
+			text.WriteString("В этот день подходов не было.")
+		}
+		return text.String()
+	}
+
+	loc := user.Location()
+	timed := false
+	var gaps []time.Duration
+	for i, approach := range session.Approaches {
+		clock := "--:--"
+		if !approach.CreatedAt.IsZero() {
+			clock = approach.CreatedAt.In(loc).Format("15:04")
+			timed = true
+		}
+		fmt.Fprintf(&text, "%d. %s — %d", i+1, clock, approach.Count)
+
+		// Подходы из одного сообщения записаны одновременно, перерыв между ними не показываем
+		if gap, ok := session.Gap(i); ok && gap > 0 {
+			fmt.Fprintf(&text, " · перерыв %s", formatGap(gap))
+			gaps = append(gaps, gap)
+		}
+		text.WriteString("\n")
+	}
+
+	total := session.GetTotalCount()
+	fmt.Fprintf(&text, "\nВсего: %d за %d подх., в среднем %.1f\n", total, session.GetApproachCount(), session.GetAveragePerApproach())
+	fmt.Fprintf(&text, "Лучший подход: %d\n", session.GetBestApproach())
+	if len(gaps) > 0 {
+		var sum time.Duration
+		for _, gap := range gaps {
+			sum += gap
+		}
+		fmt.Fprintf(&text, "Средний перерыв: %s\n", formatGap(sum/time.Duration(len(gaps))))
+	}
+
+	switch {
+	case user.DailyGoal > 0 && total >= user.DailyGoal:
+		fmt.Fprintf(&text, "\n🎯 Цель %d выполнена! ✅ %s %d%%", user.DailyGoal, goalBar(total, user.DailyGoal), total*100/user.DailyGoal)
+	case user.DailyGoal > 0:
+		fmt.Fprintf(&text, "\n🎯 Цель: %d из %d, осталось %d\n%s %d%%", total, user.DailyGoal, user.DailyGoal-total,
+			goalBar(total, user.DailyGoal), total*100/user.DailyGoal)
+	case isToday:
+		text.WriteString("\n🎯 Дневная цель не задана: /goal 50")
+	}
+
+	if timed && user.Timezone == "" {
+		text.WriteString("\n\nВремя указано по UTC. Свой часовой пояс: /timezone +3")
+	}
+
+	return strings.TrimSuffix(text.String(), "\n")
+}
+
+// goalBar рисует полоску выполнения дневной цели
+func goalBar(total, goal int) string {
+	filled := min(goalBarWidth, total*goalBarWidth/goal)
+	return strings.Repeat("▓", filled) + strings.Repeat("░", goalBarWidth-filled)
+}
+
+// formatGap форматирует перерыв между подходами с точностью до минуты
+func formatGap(gap time.Duration) string {
+	if gap < time.Minute {
+		return fmt.Sprintf("%d с", gap/time.Second)
+	}
+
+	gap = gap.Round(time.Minute)
+	hours, minutes := gap/time.Hour, (gap%time.Hour)/time.Minute
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d мин", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d ч", hours)
+	default:
+		return fmt.Sprintf("%d ч %d мин", hours, minutes)
+	}
+}
+
+// parseDay разбирает день из аргумента команды: дату в одном из dayLayouts или слово из
+// relativeDays. Дата без года — ближайшая прошедшая. today — сегодняшний день пользователя
+func parseDay(arg string, today time.Time) (time.Time, bool) {
+	arg = strings.ToLower(strings.TrimSpace(arg))
+	if offset, ok := relativeDays[arg]; ok {
+		return today.AddDate(0, 0, offset), true
+	}
+
+	for _, layout := range dayLayouts {
+		day, err := time.Parse(layout, arg)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			day = day.AddDate(today.Year(), 0, 0)
+			if day.After(today) {
+				day = day.AddDate(-1, 0, 0)
+			}
+		}
+		return day, true
+	}
+
+	return time.Time{}, false
+}
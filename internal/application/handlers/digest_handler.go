@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/scheduler"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// digestPollInterval как часто проверяется, кому пора отправить сводку
+const digestPollInterval = time.Minute
+
+// digestNowArg аргумент /digest, который показывает сводку за текущую неделю
+const digestNowArg = "now"
+
+// weekdayNames короткие названия дней недели с понедельника
+var weekdayNames = [7]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+
+// DigestHandler обрабатывает команды /digest и /timezone и рассылает еженедельные сводки
+type DigestHandler struct {
+	digestService *services.DigestService
+	userService   *services.UserService
+	router        *router.Router
+	logger        *zerolog.Logger
+}
+
+// NewDigestHandler создает обработчик еженедельной сводки и регистрирует его команды в роутере
+func NewDigestHandler(digestService *services.DigestService, userService *services.UserService, r *router.Router, logger *zerolog.Logger) *DigestHandler {
+	h := &DigestHandler{
+		digestService: digestService,
+		userService:   userService,
+		router:        r,
+		logger:        logger,
+	}
+	h.register()
+
+	return h
+}
+
+func (h *DigestHandler) register() {
+	h.router.Register(router.Command{
+		Names:       []string{"/digest"},
+		Description: "сводка за неделю в ее последний день",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleDigest(ctx, req.User, req.Args)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/timezone"},
+		Description: "часовой пояс для сводки",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleTimezone(ctx, req.User, req.Args)
+		},
+	})
+}
+
+// HandleDigest обрабатывает команду /digest [on | off | now]
+func (h *DigestHandler) HandleDigest(ctx context.Context, user *models.User, args string) *response.Response {
+	arg := strings.ToLower(strings.TrimSpace(args))
+	switch {
+	case arg == "":
+		return h.handleOverview(user)
+	case arg == digestNowArg:
+		digest, err := h.digestService.Current(ctx, user)
+		if err != nil {
+			return response.Message("Ошибка при получении сводки. Попробуйте позже.")
+		}
+		return response.New(formatDigest(digest))
+	case slices.Contains(switchOnArgs, arg), slices.Contains(switchOffArgs, arg):
+		enabled := slices.Contains(switchOnArgs, arg)
+		if err := h.digestService.SetWeeklyDigest(ctx, user, enabled); err != nil {
+			return response.Message("Ошибка при сохранении настройки. Попробуйте позже.")
+		}
+		if !enabled {
+			return response.Message("🔕 Сводка за неделю выключена.")
+		}
+		return response.Message(fmt.Sprintf("📅 Сводка за неделю включена: по %s в %d:00 (%s). "+
+			"Изменить часовой пояс: /timezone Europe/Moscow или /timezone +3", digestWeekday(user), models.DigestHour, formatTimezone(user)))
+	default:
+		return response.Message("Использование: /digest on, /digest off или /digest now — сводка за эту неделю")
+	}
+}
+
+// handleOverview показывает настройку сводки и кнопки управления
+func (h *DigestHandler) handleOverview(user *models.User) *response.Response {
+	text := "📅 Сводка за неделю выключена."
+	toggle := response.InlineButton{Text: "🔔 Включить", Data: "/digest " + switchOnArgs[0]}
+	if user.WeeklyDigest {
+		text = fmt.Sprintf("📅 Сводка за неделю приходит по %s в %d:00.", digestWeekday(user), models.DigestHour)
+		toggle = response.InlineButton{Text: "🔕 Выключить", Data: "/digest " + switchOffArgs[0]}
+	}
+	text += fmt.Sprintf("\n\nЧасовой пояс: %s. Изменить: /timezone Europe/Moscow или /timezone +3", formatTimezone(user))
+	text += "\nВ сводке: сумма и сравнение с прошлой неделей, дни тренировок, лучший день, " +
+		"выполнение дневной цели (/goal) и серия."
+
+	return response.MessageWithKeyboard(text, response.InlineKeyboard{Rows: [][]response.InlineButton{
+		{toggle, {Text: "📊 Сводка сейчас", Data: "/digest " + digestNowArg}},
+	}})
+}
+
+// HandleTimezone обрабатывает команду /timezone <пояс>
+func (h *DigestHandler) HandleTimezone(ctx context.Context, user *models.User, args string) *response.Response {
+	if strings.TrimSpace(args) == "" {
+		return response.Message(fmt.Sprintf("Часовой пояс: %s.\n\nИзменить: /timezone Europe/Moscow или /timezone +3", formatTimezone(user)))
+	}
+
+	err := h.userService.SetTimezone(ctx, user, args)
+	switch err {
+	case nil:
+		local := time.Now().In(user.Location())
+		return response.Message(fmt.Sprintf("🕒 Часовой пояс: %s, у вас сейчас %s.", formatTimezone(user), local.Format("15:04")))
+	case errors.ErrInvalidTimezone:
+		return response.Message("Не знаю такой часовой пояс. Укажите название, например Europe/Moscow, " +
+			"или смещение от UTC в часах, например +3")
+	default:
+		return response.Message("Ошибка при сохранении часового пояса. Попробуйте позже.")
+	}
+}
+
+// Job возвращает задачу планировщика, которая рассылает сводки вечером в последний день недели
+func (h *DigestHandler) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:  "weekly_digest",
+		Every: digestPollInterval,
+		Run: func(ctx context.Context) (*response.Response, error) {
+			digests, err := h.digestService.Due(ctx, time.Now())
+
+			// Сводки, собранные до ошибки, уже отмечены отправленными, поэтому отправляем их
+			resp := response.None()
+			for _, digest := range digests {
+				text := formatDigest(digest)
+				text.ChatID = digest.UserID
+				resp.Add(text)
+			}
+			return resp, err
+		},
+	}
+}
+
+// formatDigest форматирует сводку за неделю с графиком по дням
+func formatDigest(digest *models.WeeklyDigest) response.Text {
+	weekEnd := digest.WeekStart.AddDate(0, 0, 6)
+	text := fmt.Sprintf("📅 <b>Итоги недели %s–%s</b>\n\n", digest.WeekStart.Format("02.01"), weekEnd.Format("02.01"))
+
+	total := digest.Total()
+	if total == 0 {
+		text += "На этой неделе отжиманий не было. Новая неделя — новый старт! 💪"
+		return response.Text{Text: text, ParseMode: response.ParseModeHTML}
+	}
+
+	text += fmt.Sprintf("Всего: %d отжиманий", total)
+	if change, ok := digest.Change(); ok {
+		text += fmt.Sprintf(" (%+.0f%% к прошлой неделе: %d)", change, digest.PreviousTotal)
+	} else {
+		text += " (на прошлой неделе отжиманий не было)"
+	}
+	text += fmt.Sprintf("\nДней тренировок: %d из 7\n", digest.TrainingDays())
+	bestDay, bestCount := digest.BestDay()
+	text += fmt.Sprintf("Лучший день: %s — %d\n", digestDayName(digest, bestDay), bestCount)
+
+	if digest.DailyGoal > 0 {
+		text += fmt.Sprintf("🎯 Цель %d в день выполнена в %d из 7 дней\n", digest.DailyGoal, digest.GoalDays())
+	} else {
+		text += "🎯 Дневная цель не задана: /goal 50\n"
+	}
+	if digest.Streak > 0 {
+		text += fmt.Sprintf("🔥 Серия: %d дн. подряд — не прерывайте!\n", digest.Streak)
+	} else {
+		text += "🔥 Серия прервалась — начните новую завтра!\n"
+	}
+
+	text += "\n<pre>" + digestChart(digest) + "</pre>"
+	return response.Text{Text: text, ParseMode: response.ParseModeHTML}
+}
+
+// digestChart рисует столбики отжиманий по дням недели
+func digestChart(digest *models.WeeklyDigest) string {
+	const width = 12
+
+	_, best := digest.BestDay()
+	var chart strings.Builder
+	for i, count := range digest.Days {
+		bar := 0
+		if best > 0 {
+			bar = (count*width + best - 1) / best
+		}
+		fmt.Fprintf(&chart, "%s %-*s %d\n", digestDayName(digest, i), width, strings.Repeat("█", bar), count)
+	}
+	return strings.TrimSuffix(chart.String(), "\n")
+}
+
+// digestDayName возвращает короткое название i-го дня недели сводки
+func digestDayName(digest *models.WeeklyDigest, i int) string {
+	return weekdayNames[weekdayIndex(digest.WeekStart.AddDate(0, 0, i).Weekday())]
+}
+
+// digestWeekday возвращает, по каким дням приходит сводка: по последним дням недели пользователя
+func digestWeekday(user *models.User) string {
+	return weekdayDative[weekdayIndex((user.WeekStart+6)%7)]
+}
+
+// formatTimezone форматирует часовой пояс пользователя
+func formatTimezone(user *models.User) string {
+	if user.Timezone == "" {
+		return "UTC"
+	}
+	return user.Timezone
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"azhumania/internal/application/chart"
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"azhumania/internal/logging"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// inlineCacheTTL сколько бот хранит посчитанные результаты пользователя: inline запрос
+	// приходит на каждую набранную букву, а статистика за это время почти не меняется
+	inlineCacheTTL = time.Minute
+	// inlineTelegramCacheTime сколько Telegram может отдавать ответ без запроса к боту
+	inlineTelegramCacheTime = time.Minute
+	// inlineStartParam параметр /start для кнопки перехода в бота у незарегистрированных
+	inlineStartParam = "inline"
+)
+
+// inlineResult результат inline запроса и слова запроса, по которым он находится
+type inlineResult struct {
+	keywords []string
+	result   response.InlineResult
+}
+
+// inlineCacheEntry посчитанные результаты пользователя
+type inlineCacheEntry struct {
+	results   []inlineResult
+	expiresAt time.Time
+}
+
+// InlineHandler отвечает на inline запросы: пользователь набирает @бот в любом чате и
+// отправляет туда свою статистику за неделю, за сегодня или график по дням. Пересчет
+// результатов ограничен лимитом тяжелых команд: запросы из кэша не ограничиваются, иначе
+// лимит кончался бы на наборе текста
+type InlineHandler struct {
+	userService  *services.UserService
+	shareService *services.ShareService
+	rateLimit    *ratelimit.Policy // nil — без ограничения
+	publicURL    string            // адрес HTTP сервера для ссылок на графики, пусто — без графика
+	logger       *zerolog.Logger
+
+	mu    sync.Mutex
+	cache map[int64]inlineCacheEntry
+}
+
+// NewInlineHandler создает обработчик inline запросов
+func NewInlineHandler(userService *services.UserService, shareService *services.ShareService, rateLimit *ratelimit.Policy, publicURL string, logger *zerolog.Logger) *InlineHandler {
+	return &InlineHandler{
+		userService:  userService,
+		shareService: shareService,
+		rateLimit:    rateLimit,
+		publicURL:    strings.TrimSuffix(publicURL, "/"),
+		logger:       logger,
+		cache:        make(map[int64]inlineCacheEntry),
+	}
+}
+
+// Handle отвечает на inline запрос результатами, подходящими под текст запроса
+func (h *InlineHandler) Handle(ctx context.Context, query *tgbotapi.InlineQuery) *response.InlineAnswer {
+	answer := &response.InlineAnswer{CacheTime: inlineTelegramCacheTime, Personal: true}
+
+	user, err := h.userService.GetUser(ctx, query.From.ID)
+	switch {
+	case err == errors.ErrUserNotFound:
+		answer.StartText = "Начать тренировки"
+		answer.StartParam = inlineStartParam
+		return answer
+	case err != nil:
+		// Без CacheTime Telegram повторит запрос, когда база снова будет доступна
+		answer.CacheTime = 0
+		return answer
+	case user.IsBanned():
+		return answer
+	}
+
+	results, err := h.results(ctx, user)
+	if err != nil {
+		// Без CacheTime Telegram спросит снова, когда лимит или база позволят ответить
+		answer.CacheTime = 0
+		return answer
+	}
+
+	search := strings.ToLower(strings.TrimSpace(query.Query))
+	for _, result := range results {
+		if matchesInline(result.keywords, search) {
+			answer.Results = append(answer.Results, result.result)
+		}
+	}
+	return answer
+}
+
+// results возвращает результаты пользователя из кэша или считает их заново
+func (h *InlineHandler) results(ctx context.Context, user *models.User) ([]inlineResult, error) {
+	now := time.Now()
+
+	h.mu.Lock()
+	entry, ok := h.cache[user.ID]
+	h.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.results, nil
+	}
+
+	if !h.rateLimit.AllowExpensive(ctx, user.ID) {
+		return nil, errors.ErrRateLimited
+	}
+	results, err := h.build(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	for id, entry := range h.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(h.cache, id)
+		}
+	}
+	h.cache[user.ID] = inlineCacheEntry{results: results, expiresAt: now.Add(inlineCacheTTL)}
+	h.mu.Unlock()
+
+	return results, nil
+}
+
+// Forget удаляет посчитанные результаты пользователя, например после удаления аккаунта
+func (h *InlineHandler) Forget(userID int64) {
+	h.mu.Lock()
+	delete(h.cache, userID)
+	h.mu.Unlock()
+}
+
+// build считает статистику пользователя и собирает результаты
+func (h *InlineHandler) build(ctx context.Context, user *models.User) ([]inlineResult, error) {
+	stats, err := h.shareService.Stats(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	weekText := fmt.Sprintf("💪 Моя неделя: %d отжиманий, дней тренировок: %d", stats.Week.Total(), stats.Week.TrainingDays())
+	todayText := fmt.Sprintf("💪 Сегодня: %d отжиманий", stats.Today.GetTotalCount())
+	if approaches := len(stats.Today.Approaches); approaches > 0 {
+		todayText += fmt.Sprintf(" за %d подх.", approaches)
+	}
+
+	results := []inlineResult{
+		{
+			keywords: []string{"неделя", "week"},
+			result: response.InlineResult{
+				ID:          "week",
+				Title:       fmt.Sprintf("Моя неделя: %d", stats.Week.Total()),
+				Description: fmt.Sprintf("%d отжиманий, дней тренировок: %d", stats.Week.Total(), stats.Week.TrainingDays()),
+				Text:        weekText,
+			},
+		},
+		{
+			keywords: []string{"сегодня", "today"},
+			result: response.InlineResult{
+				ID:          "today",
+				Title:       fmt.Sprintf("Сегодня: %d", stats.Today.GetTotalCount()),
+				Description: "Отжимания за сегодня",
+				Text:        todayText,
+			},
+		},
+	}
+
+	if photo, ok := h.chartResult(ctx, user, stats.Week); ok {
+		results = append(results, inlineResult{keywords: []string{"график", "chart"}, result: photo})
+	}
+	return results, nil
+}
+
+// chartResult рисует график недели и сохраняет его для ссылки. Без публичного адреса или
+// при ошибке график не предлагается, остальные результаты остаются
+func (h *InlineHandler) chartResult(ctx context.Context, user *models.User, digest *models.WeeklyDigest) (response.InlineResult, bool) {
+	if h.publicURL == "" {
+		return response.InlineResult{}, false
+	}
+
+	selected := int(user.Today().Sub(digest.WeekStart).Hours() / 24)
+
+	image, err := chart.WeekBars(digest.Days, selected)
+	if err != nil {
+		logging.FromContext(ctx, h.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to draw week chart")
+		return response.InlineResult{}, false
+	}
+	id, err := h.shareService.SaveChart(ctx, image)
+	if err != nil {
+		return response.InlineResult{}, false
+	}
+
+	days := make([]string, len(digest.Days))
+	for i, count := range digest.Days {
+		days[i] = fmt.Sprintf("%s %d", digestDayName(digest, i), count)
+	}
+
+	return response.InlineResult{
+		ID:          "chart",
+		Title:       "График недели",
+		Description: fmt.Sprintf("Всего: %d", digest.Total()),
+		Text:        fmt.Sprintf("📊 Моя неделя: %d отжиманий\n%s", digest.Total(), strings.Join(days, " · ")),
+		PhotoURL:    h.publicURL + "/charts/" + id + ".jpg",
+	}, true
+}
+
+// matchesInline проверяет, что текст запроса пустой или с него начинается одно из слов
+func matchesInline(keywords []string, search string) bool {
+	if search == "" {
+		return true
+	}
+	for _, keyword := range keywords {
+		if strings.HasPrefix(keyword, search) {
+			return true
+		}
+	}
+	return false
+}
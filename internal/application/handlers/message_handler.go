@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"azhumania/internal/application/entry"
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
 	"azhumania/internal/application/services"
 	"azhumania/internal/domain/errors"
 	"azhumania/internal/domain/models"
+	"azhumania/internal/logging"
 	"context"
+	stderrors "errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,46 +23,57 @@ import (
 type MessageHandler struct {
 	userService    *services.UserService
 	pushupService  *services.PushupService
+	programService *services.ProgramService
+	timerService   *services.TimerService
 	commandHandler *CommandHandler
+	router         *router.Router
 	logger         *zerolog.Logger
 }
 
-// NewMessageHandler создает новый обработчик сообщений
+// NewMessageHandler создает новый обработчик сообщений и настраивает цепочку middleware роутера
 func NewMessageHandler(
 	userService *services.UserService,
 	pushupService *services.PushupService,
+	programService *services.ProgramService,
+	timerService *services.TimerService,
 	commandHandler *CommandHandler,
+	r *router.Router,
+	rateLimit *ratelimit.Policy,
 	logger *zerolog.Logger,
 ) *MessageHandler {
-	return &MessageHandler{
+	h := &MessageHandler{
 		userService:    userService,
 		pushupService:  pushupService,
+		programService: programService,
+		timerService:   timerService,
 		commandHandler: commandHandler,
+		router:         r,
 		logger:         logger,
 	}
+
+	// Ограничение частоты стоит до загрузки пользователя, чтобы не нагружать базу
+	r.Use(
+		router.Recover(logger),
+		router.Logging(logger),
+		router.Metrics(),
+		router.RateLimit(rateLimit),
+		router.LoadUser(h.getOrCreateUser, logger),
+		router.Authorize(),
+	)
+	r.Fallback(func(ctx context.Context, req *router.Request) *response.Response {
+		return h.handlePushupCount(ctx, req.Text, req.User)
+	})
+
+	return h
 }
 
-// Handle обрабатывает входящее сообщение
-func (h *MessageHandler) Handle(ctx context.Context, msg *tgbotapi.Message) (string, interface{}) {
+// Handle обрабатывает входящее сообщение и возвращает действия, которые нужно выполнить в ответ
+func (h *MessageHandler) Handle(ctx context.Context, msg *tgbotapi.Message) *response.Response {
 	if msg == nil {
-		return "Ошибка: пустое сообщение", nil
+		return response.Message("Ошибка: пустое сообщение")
 	}
 
-	// Получаем или создаем пользователя
-	user, err := h.getOrCreateUser(ctx, msg)
-	if err != nil {
-		fmt.Println("@@@@@@@@@@@@@@@@@@error getOrCreateUser", err)
-		h.logger.Error().Err(err).Int64("telegramID", msg.From.ID).Msg("failed to get or create user")
-		return "Произошла ошибка. Попробуйте позже.", nil
-	}
-
-	// Проверяем, является ли сообщение командой или кнопкой
-	if h.isCommand(msg.Text) || h.isButton(msg.Text) {
-		return h.handleCommand(ctx, msg.Text, user)
-	}
-
-	// Обрабатываем количество отжиманий
-	return h.handlePushupCount(ctx, msg.Text, user)
+	return h.router.Handle(ctx, msg)
 }
 
 // getOrCreateUser получает или создает пользователя
@@ -74,76 +91,108 @@ func (h *MessageHandler) getOrCreateUser(ctx context.Context, msg *tgbotapi.Mess
 	return h.userService.GetOrCreateUser(ctx, msg.From.ID, phone, nickname)
 }
 
-// isCommand проверяет, является ли сообщение командой
-func (h *MessageHandler) isCommand(text string) bool {
-	return strings.HasPrefix(text, "/")
-}
-
-// isButton проверяет, является ли сообщение нажатием кнопки
-func (h *MessageHandler) isButton(text string) bool {
-	buttons := []string{
-		"📊 Статистика",
-		"❓ Помощь",
-		"🏠 Главное меню",
-	}
-
-	for _, button := range buttons {
-		if text == button {
-			return true
-		}
-	}
-	return false
-}
-
-// handleCommand обрабатывает команды и кнопки
-func (h *MessageHandler) handleCommand(ctx context.Context, command string, user *models.User) (string, interface{}) {
-	switch command {
-	case "/start", "🏠 Главное меню":
-		return h.commandHandler.HandleStart(ctx, user)
-	case "/help", "❓ Помощь":
-		return h.commandHandler.HandleHelp(ctx, user)
-	case "/stats", "📊 Статистика":
-		return h.commandHandler.HandleStats(ctx, user)
-	default:
-		return h.commandHandler.HandleUnknownCommand(ctx, command)
+// handlePushupCount обрабатывает количество отжиманий: одно число или несколько подходов
+// вида "15+15+10", "3x20", "15 20 10", в том числе словами: "двадцать пять"
+func (h *MessageHandler) handlePushupCount(ctx context.Context, text string, user *models.User) *response.Response {
+	if !entry.HasNumber(text) {
+		return response.Message("Пожалуйста, отправьте число отжиманий (например: 15) или используйте команду /help")
 	}
-}
 
-// handlePushupCount обрабатывает количество отжиманий
-func (h *MessageHandler) handlePushupCount(ctx context.Context, text string, user *models.User) (string, interface{}) {
-	// Парсим количество отжиманий
-	count, err := strconv.Atoi(text)
+	// Разбираем подходы
+	parts, err := entry.Parse(text)
 	if err != nil {
-		return "Пожалуйста, отправьте число отжиманий (например: 15) или используйте команду /help", nil
+		return response.Message(h.formatEntryError(text, err))
 	}
+	counts := entry.Counts(parts)
 
-	// Добавляем подход отжиманий
-	session, err := h.pushupService.AddPushupApproach(ctx, user.ID, count)
+	// Добавляем подходы отжиманий
+	session, records, err := h.pushupService.AddPushupApproaches(ctx, user, counts)
 	if err != nil {
-		h.logger.Error().Err(err).Int64("userID", user.ID).Int("count", count).Msg("failed to add pushup approach")
+		logging.FromContext(ctx, h.logger).Error().Err(err).Int64("userID", user.ID).Ints("counts", counts).Msg("failed to add pushup approaches")
+
+		prefix := ""
+		var approachErr *errors.ApproachError
+		if stderrors.As(err, &approachErr) && len(parts) > 1 {
+			prefix = fmt.Sprintf("Подход %d («%s»): ", approachErr.Index+1, parts[approachErr.Index].Text)
+		}
 
-		switch err {
-		case errors.ErrInvalidPushupCount:
-			return "Количество отжиманий должно быть больше 0", nil
-		case errors.ErrPushupCountTooHigh:
-			return "Количество отжиманий не может быть больше 1000 за раз", nil
+		switch {
+		case stderrors.Is(err, errors.ErrInvalidPushupCount):
+			return response.Message(prefix + "количество отжиманий должно быть больше 0")
+		case stderrors.Is(err, errors.ErrPushupCountTooHigh):
+			return response.Message(prefix + "количество отжиманий не может быть больше 1000 за раз")
 		default:
-			return "Произошла ошибка при сохранении данных. Попробуйте позже.", nil
+			return response.Message("Произошла ошибка при сохранении данных. Попробуйте позже.")
 		}
 	}
 
+	// Запускаем отсчет отдыха заново; ошибку записывает в лог репозиторий, подходы уже сохранены
+	_ = h.timerService.Start(ctx, user)
+
 	// Формируем ответ
-	return h.formatPushupResponse(session, count), nil
+	reply := h.formatPushupResponse(session, parts, records)
+
+	// Продвигаем программу тренировок; ошибку записывает в лог сервис, подходы уже сохранены
+	if status, err := h.programService.RecordProgress(ctx, user, session); err == nil && status != nil {
+		reply += "\n\n" + formatProgramStatus(status)
+	}
+
+	return response.Message(reply)
+}
+
+// formatEntryError объясняет, какую часть сообщения не удалось разобрать
+func (h *MessageHandler) formatEntryError(text string, err error) string {
+	const examples = "\n\nПримеры: 15, 15+15+10, 3x20, 15 20 10, двадцать пять"
+
+	var entryErr *entry.Error
+	if !stderrors.As(err, &entryErr) {
+		return "Не удалось разобрать сообщение." + examples
+	}
+
+	switch entryErr.Kind {
+	case entry.ErrorMissingNumber:
+		return fmt.Sprintf("Не хватает числа рядом с «%s»: %s%s", entryErr.Token, entry.Highlight(text, entryErr), examples)
+	case entry.ErrorTooManyApproaches:
+		return fmt.Sprintf("Слишком много подходов в одном сообщении, не больше %d.", entry.MaxApproaches)
+	case entry.ErrorNumberTooLarge:
+		return fmt.Sprintf("Слишком большое число «%s». Количество отжиманий не может быть больше 1000 за раз.", entryErr.Token)
+	default:
+		return fmt.Sprintf("Не понял «%s»: %s%s", entryErr.Token, entry.Highlight(text, entryErr), examples)
+	}
 }
 
 // formatPushupResponse форматирует ответ с результатами отжиманий
-func (h *MessageHandler) formatPushupResponse(session *models.PushupSession, lastCount int) string {
-	response := fmt.Sprintf("✅ Сохранено %d отжиманий!\n\n", lastCount)
+func (h *MessageHandler) formatPushupResponse(session *models.PushupSession, parts []entry.Part, records []models.BrokenRecord) string {
+	var response string
+	if len(parts) == 1 {
+		response = fmt.Sprintf("✅ Сохранено %d отжиманий!\n\n", parts[0].Count)
+	} else {
+		total := 0
+		counts := make([]string, len(parts))
+		for i, part := range parts {
+			total += part.Count
+			counts[i] = strconv.Itoa(part.Count)
+		}
+		response = fmt.Sprintf("✅ Сохранено подходов: %d (%s = %d отжиманий)!\n", len(parts), strings.Join(counts, " + "), total)
+		// Множитель можно перепутать местами: показываем, как он понят
+		for _, part := range entry.Multiplied(parts) {
+			response += fmt.Sprintf("«%s»: подходов — %d, в каждом — %d\n", part.Text, part.Sets, part.Count)
+		}
+		response += "\n"
+	}
 	response += "📊 Статистика за сегодня:\n"
 	response += fmt.Sprintf("   • Всего отжиманий: %d\n", session.GetTotalCount())
 	response += fmt.Sprintf("   • Подходов: %d\n", session.GetApproachCount())
 	response += fmt.Sprintf("   • Среднее за подход: %.1f\n", session.GetAveragePerApproach())
 
+	// Объявляем побитые рекорды
+	if len(records) > 0 {
+		response += "\n🏆 Новый рекорд!\n"
+		for _, record := range records {
+			response += fmt.Sprintf("   • %s: %s (было %d)\n", recordTitle(record.Kind), formatRecordValue(record.PersonalRecord), record.Previous)
+		}
+	}
+
 	// Добавляем мотивацию
 	response += h.getMotivationMessage(session.GetTotalCount())
 
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"azhumania/internal/domain/models"
+	"strings"
+	"time"
+)
+
+// statsPreset готовый период для /stats
+type statsPreset struct {
+	arg     string   // аргумент /stats и данные кнопки
+	aliases []string // другие написания аргумента
+	title   string
+	period  func(today time.Time, user *models.User) models.Period
+}
+
+// statsPresets готовые периоды в порядке кнопок. Текущие неделя, месяц и год заканчиваются
+// сегодняшним днем, чтобы среднее за день периода не занижали еще не наступившие дни
+var statsPresets = []statsPreset{
+	{arg: "week", aliases: []string{"неделя"}, title: "Эта неделя", period: func(today time.Time, user *models.User) models.Period {
+		return models.NewPeriod(models.WeekStartOn(today, user.WeekStart), today)
+	}},
+	{arg: "7d", aliases: []string{"7"}, title: "7 дней", period: func(today time.Time, _ *models.User) models.Period {
+		return models.LastDays(today, 7)
+	}},
+	{arg: "30d", aliases: []string{"30"}, title: "30 дней", period: func(today time.Time, _ *models.User) models.Period {
+		return models.LastDays(today, 30)
+	}},
+	{arg: "90d", aliases: []string{"90"}, title: "90 дней", period: func(today time.Time, _ *models.User) models.Period {
+		return models.LastDays(today, 90)
+	}},
+	{arg: "month", aliases: []string{"месяц"}, title: "Этот месяц", period: func(today time.Time, _ *models.User) models.Period {
+		return models.NewPeriod(models.MonthOf(today).From, today)
+	}},
+	{arg: "lastmonth", title: "Прошлый месяц", period: func(today time.Time, _ *models.User) models.Period {
+		return models.MonthOf(models.MonthOf(today).From.AddDate(0, 0, -1))
+	}},
+	{arg: "year", aliases: []string{"год"}, title: "С начала года", period: func(today time.Time, _ *models.User) models.Period {
+		return models.NewPeriod(time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC), today)
+	}},
+	{arg: "all", aliases: []string{"всё", "все"}, title: "Всё время", period: func(today time.Time, _ *models.User) models.Period {
+		// Начало уточняется по первой тренировке
+		return models.Period{To: today}
+	}},
+}
+
+// findStatsPreset находит готовый период по аргументу /stats
+func findStatsPreset(arg string) (statsPreset, bool) {
+	for _, preset := range statsPresets {
+		if preset.arg == arg {
+			return preset, true
+		}
+		for _, alias := range preset.aliases {
+			if alias == arg {
+				return preset, true
+			}
+		}
+	}
+	return statsPreset{}, false
+}
+
+// periodSeparators разделители дат периода, которые заменяются пробелом: 01.10–15.10, 01.10..15.10
+var periodSeparators = strings.NewReplacer("—", " ", "–", " ", "..", " ", " - ", " ")
+
+// parsePeriod разбирает период из двух дат или одной даты, от которой период идет до сегодня.
+// Конец периода после сегодня сдвигается на сегодня. Возвращает false, если даты не разобраны
+// или начало позже конца
+func parsePeriod(args string, today time.Time) (models.Period, bool) {
+	fields := strings.Fields(periodSeparators.Replace(args))
+	if len(fields) == 0 || len(fields) > 2 {
+		return models.Period{}, false
+	}
+
+	from, ok := parseDay(fields[0], today)
+	if !ok {
+		return models.Period{}, false
+	}
+	to := today
+	if len(fields) == 2 {
+		if to, ok = parseDay(fields[1], today); !ok {
+			return models.Period{}, false
+		}
+	}
+
+	if to.After(today) {
+		to = today
+	}
+	if from.After(to) {
+		return models.Period{}, false
+	}
+	return models.NewPeriod(from, to), true
+}
+
+// weekdayIndex возвращает номер дня недели с понедельника для weekdayNames
+func weekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// weekdayGenitive названия дней недели с понедельника для «неделя начинается с ...»
+var weekdayGenitive = [7]string{"понедельника", "вторника", "среды", "четверга", "пятницы", "субботы", "воскресенья"}
+
+// weekdayDative названия дней недели с понедельника для «приходит по ...»
+var weekdayDative = [7]string{"понедельникам", "вторникам", "средам", "четвергам", "пятницам", "субботам", "воскресеньям"}
+
+// weekdayArgs написания дней недели в аргументах команд
+var weekdayArgs = map[string]time.Weekday{
+	"пн": time.Monday, "понедельник": time.Monday, "mon": time.Monday, "monday": time.Monday,
+	"вт": time.Tuesday, "вторник": time.Tuesday, "tue": time.Tuesday, "tuesday": time.Tuesday,
+	"ср": time.Wednesday, "среда": time.Wednesday, "wed": time.Wednesday, "wednesday": time.Wednesday,
+	"чт": time.Thursday, "четверг": time.Thursday, "thu": time.Thursday, "thursday": time.Thursday,
+	"пт": time.Friday, "пятница": time.Friday, "fri": time.Friday, "friday": time.Friday,
+	"сб": time.Saturday, "суббота": time.Saturday, "sat": time.Saturday, "saturday": time.Saturday,
+	"вс": time.Sunday, "воскресенье": time.Sunday, "sun": time.Sunday, "sunday": time.Sunday,
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"azhumania/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestParsePeriod(t *testing.T) {
+	today := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		args string
+		from string
+		to   string
+	}{
+		{"01.10.2026 15.10.2026", "2026-10-01", "2026-10-15"},
+		{"01.10–15.10", "2026-10-01", "2026-10-15"},
+		{"01.10..15.10", "2026-10-01", "2026-10-15"},
+		{"01.10 - 15.10", "2026-10-01", "2026-10-15"},
+		{"2026-10-01", "2026-10-01", "2026-10-19"},
+		{"вчера", "2026-10-18", "2026-10-19"},
+		{"позавчера вчера", "2026-10-17", "2026-10-18"},
+		{"сегодня", "2026-10-19", "2026-10-19"},
+		// Дата без года — ближайшая прошедшая
+		{"01.12", "2025-12-01", "2026-10-19"},
+		// Конец после сегодня сдвигается на сегодня
+		{"01.10.2026 31.10.2026", "2026-10-01", "2026-10-19"},
+		{"", "", ""},
+		{"01.10 15.10 20.10", "", ""},
+		{"15.10 01.10", "", ""},
+		{"31.02.2026", "", ""},
+		{"завтра", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			period, ok := parsePeriod(tt.args, today)
+			if tt.from == "" {
+				if ok {
+					t.Fatalf("parsePeriod(%q) = %v, want false", tt.args, period)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("parsePeriod(%q) = false, want %s – %s", tt.args, tt.from, tt.to)
+			}
+			if from, to := period.From.Format("2006-01-02"), period.To.Format("2006-01-02"); from != tt.from || to != tt.to {
+				t.Errorf("parsePeriod(%q) = %s – %s, want %s – %s", tt.args, from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestStatsPresets(t *testing.T) {
+	// Понедельник
+	today := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	monday := &models.User{WeekStart: time.Monday}
+	sunday := &models.User{WeekStart: time.Sunday}
+
+	tests := []struct {
+		arg  string
+		user *models.User
+		from string
+		to   string
+		days int
+	}{
+		{"week", monday, "2026-10-19", "2026-10-19", 1},
+		{"week", sunday, "2026-10-18", "2026-10-19", 2},
+		{"7d", monday, "2026-10-13", "2026-10-19", 7},
+		{"30d", monday, "2026-09-20", "2026-10-19", 30},
+		{"month", monday, "2026-10-01", "2026-10-19", 19},
+		{"lastmonth", monday, "2026-09-01", "2026-09-30", 30},
+		{"year", monday, "2026-01-01", "2026-10-19", 292},
+	}
+
+	for _, tt := range tests {
+		preset, ok := findStatsPreset(tt.arg)
+		if !ok {
+			t.Fatalf("findStatsPreset(%q) not found", tt.arg)
+		}
+		period := preset.period(today, tt.user)
+		if from, to := period.From.Format("2006-01-02"), period.To.Format("2006-01-02"); from != tt.from || to != tt.to || period.Days() != tt.days {
+			t.Errorf("%s for week from %s = %s – %s (%d days), want %s – %s (%d days)",
+				tt.arg, tt.user.WeekStart, from, to, period.Days(), tt.from, tt.to, tt.days)
+		}
+	}
+}
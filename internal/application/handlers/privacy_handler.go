@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// deleteConfirmArg аргумент /deleteme, подтверждающий удаление
+const deleteConfirmArg = "confirm"
+
+// PrivacyHandler обрабатывает команды /mydata и /deleteme
+type PrivacyHandler struct {
+	privacyService *services.PrivacyService
+	inline         *InlineHandler // результаты inline запросов удаленного пользователя забываются
+	router         *router.Router
+	logger         *zerolog.Logger
+}
+
+// NewPrivacyHandler создает обработчик команд о данных пользователя и регистрирует их в роутере
+func NewPrivacyHandler(privacyService *services.PrivacyService, inline *InlineHandler, r *router.Router, logger *zerolog.Logger) *PrivacyHandler {
+	h := &PrivacyHandler{
+		privacyService: privacyService,
+		inline:         inline,
+		router:         r,
+		logger:         logger,
+	}
+	h.register()
+
+	return h
+}
+
+func (h *PrivacyHandler) register() {
+	h.router.Register(router.Command{
+		Names:       []string{"/mydata"},
+		Description: "какие данные о вас хранятся",
+		Expensive:   true,
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			if req.Message.Chat != nil && !req.Message.Chat.IsPrivate() {
+				return response.Message("🔒 Данные можно посмотреть только в личном чате с ботом.")
+			}
+			return h.HandleMyData(ctx, req.User)
+		},
+	})
+	h.router.Register(router.Command{
+		Names:       []string{"/deleteme"},
+		Description: "удалить аккаунт и все данные",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			if req.Message.Chat != nil && !req.Message.Chat.IsPrivate() {
+				return response.Message("🔒 Удалить аккаунт можно только в личном чате с ботом.")
+			}
+			return h.HandleDeleteMe(ctx, req.User, req.Args)
+		},
+	})
+}
+
+// HandleMyData обрабатывает команду /mydata: показывает все данные, которые хранит бот
+func (h *PrivacyHandler) HandleMyData(ctx context.Context, user *models.User) *response.Response {
+	summary, err := h.privacyService.Summary(ctx, user.ID)
+	if err != nil {
+		return response.Message("Ошибка при получении данных. Попробуйте позже.")
+	}
+
+	var text strings.Builder
+	text.WriteString("🗂 Данные, которые хранит бот:\n\n")
+	fmt.Fprintf(&text, "👤 Профиль: ID %d, имя «%s», логин %s, роль %s\n", user.ID, user.NickName, user.Phone, user.Role)
+	fmt.Fprintf(&text, "⚙️ Настройки: часовой пояс %s, %s, %s, %s\n",
+		formatTimezone(user), formatGoalSetting(user), formatTimerSetting(user), formatDigestSetting(user))
+
+	if summary.Approaches > 0 {
+		fmt.Fprintf(&text, "💪 Подходов: %d, всего %d отжиманий, с %s по %s\n", summary.Approaches, summary.Pushups,
+			summary.FirstDay.Format("02.01.2006"), summary.LastDay.Format("02.01.2006"))
+	} else {
+		text.WriteString("💪 Подходов нет\n")
+	}
+	fmt.Fprintf(&text, "🏆 Рекорды и текущий прогресс: %d записей\n", summary.PersonalRecords)
+	if summary.ProgramEnrollments > 0 {
+		text.WriteString("📋 Участие в программе тренировок: есть\n")
+	} else {
+		text.WriteString("📋 Участие в программе тренировок: нет\n")
+	}
+	fmt.Fprintf(&text, "🔑 Токенов API: %d (хранится только хэш)\n", summary.APITokens)
+	fmt.Fprintf(&text, "📅 Отметок об отправленных сводках: %d\n", summary.WeeklyDigests)
+	text.WriteString("\nВ кэше хранятся копии профиля и подходов, а также запущенный таймер отдыха. ")
+	text.WriteString("Удалить аккаунт и все данные: /deleteme")
+
+	return response.Message(text.String())
+}
+
+// HandleDeleteMe обрабатывает команду /deleteme [confirm]: без подтверждения объясняет,
+// что будет удалено, и показывает кнопку подтверждения
+func (h *PrivacyHandler) HandleDeleteMe(ctx context.Context, user *models.User, args string) *response.Response {
+	if strings.TrimSpace(args) != deleteConfirmArg {
+		text := "⚠️ Будут безвозвратно удалены ваш профиль и настройки, все подходы, рекорды, " +
+			"программа тренировок, токены API и таймер отдыха. Восстановить их будет нельзя.\n\n" +
+			"Посмотреть, что хранится: /mydata"
+		return response.MessageWithKeyboard(text, response.InlineKeyboard{Rows: [][]response.InlineButton{
+			{{Text: "🗑 Да, удалить всё", Data: "/deleteme " + deleteConfirmArg}},
+		}})
+	}
+
+	deleted, err := h.privacyService.DeleteAccount(ctx, user)
+	if err != nil {
+		return response.Message("Ошибка при удалении данных. Попробуйте еще раз позже.")
+	}
+	h.inline.Forget(user.ID)
+
+	return response.New(response.Text{
+		Text: fmt.Sprintf("🗑 Аккаунт удален: подходов %d, записей рекордов %d, токенов API %d.\n\n"+
+			"Если напишете боту снова, он начнет с чистого листа.", deleted.Approaches, deleted.PersonalRecords, deleted.APITokens),
+		Keyboard: response.RemoveKeyboard{},
+	})
+}
+
+// formatGoalSetting форматирует дневную цель для /mydata
+func formatGoalSetting(user *models.User) string {
+	if user.DailyGoal == 0 {
+		return "цель не задана"
+	}
+	return fmt.Sprintf("цель %d в день", user.DailyGoal)
+}
+
+// formatTimerSetting форматирует таймер отдыха для /mydata
+func formatTimerSetting(user *models.User) string {
+	if user.RestTimer == 0 {
+		return "таймер отдыха выключен"
+	}
+	return "таймер отдыха " + formatInterval(user.RestTimer)
+}
+
+// formatDigestSetting форматирует подписку на сводку для /mydata
+func formatDigestSetting(user *models.User) string {
+	if user.WeeklyDigest {
+		return "сводка за неделю включена"
+	}
+	return "сводка за неделю выключена"
+}
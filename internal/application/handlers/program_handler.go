@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// programStopArg аргумент /program для выхода из программы
+const programStopArg = "stop"
+
+// ProgramHandler обрабатывает команду /program: выбор программы тренировок и выход из нее
+type ProgramHandler struct {
+	programService *services.ProgramService
+	router         *router.Router
+	logger         *zerolog.Logger
+}
+
+// NewProgramHandler создает обработчик программ тренировок и регистрирует его команды в роутере
+func NewProgramHandler(programService *services.ProgramService, r *router.Router, logger *zerolog.Logger) *ProgramHandler {
+	h := &ProgramHandler{
+		programService: programService,
+		router:         r,
+		logger:         logger,
+	}
+	h.register()
+
+	return h
+}
+
+func (h *ProgramHandler) register() {
+	h.router.Register(router.Command{
+		Names:       []string{"/program"},
+		Buttons:     []string{"📋 Программа"},
+		Description: "программа тренировок",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleProgram(ctx, req.User, req.Args)
+		},
+	})
+}
+
+// HandleProgram обрабатывает команду /program [<программа> [максимум] | stop]
+func (h *ProgramHandler) HandleProgram(ctx context.Context, user *models.User, args string) *response.Response {
+	fields := strings.Fields(args)
+	switch {
+	case len(fields) == 0:
+		return h.handleOverview(ctx, user)
+	case fields[0] == programStopArg:
+		return h.handleLeave(ctx, user)
+	default:
+		initialMax := 0
+		if len(fields) > 1 {
+			value, err := strconv.Atoi(fields[1])
+			if err != nil || value <= 0 {
+				return response.Message("Максимум должен быть положительным числом, например: /program hundred 20")
+			}
+			initialMax = value
+		}
+		return h.handleEnroll(ctx, user, fields[0], initialMax)
+	}
+}
+
+// handleOverview показывает текущую программу и список программ с кнопками записи
+func (h *ProgramHandler) handleOverview(ctx context.Context, user *models.User) *response.Response {
+	status, err := h.programService.Today(ctx, user)
+	if err != nil {
+		return response.Message("Ошибка при получении программы. Попробуйте позже.")
+	}
+
+	var text strings.Builder
+	var rows [][]response.InlineButton
+	if status != nil {
+		text.WriteString(formatProgramStatus(status))
+		text.WriteString("\n\n")
+		rows = append(rows, []response.InlineButton{{Text: "🚪 Выйти из программы", Data: "/program " + programStopArg}})
+	}
+
+	text.WriteString("📋 Программы тренировок:\n")
+	for _, program := range h.programService.Programs() {
+		fmt.Fprintf(&text, "\n• %s (/program %s)\n  %s, недель: %d\n", program.Title, program.ID, program.Description, len(program.Weeks))
+		rows = append(rows, []response.InlineButton{{Text: "▶️ " + program.Title, Data: "/program " + program.ID}})
+	}
+	text.WriteString("\nДля программ от максимума можно указать его сразу: /program hundred 20. ")
+	text.WriteString("Без числа берется ваш лучший подход.")
+
+	return response.MessageWithKeyboard(text.String(), response.InlineKeyboard{Rows: rows})
+}
+
+// handleEnroll записывает пользователя в программу
+func (h *ProgramHandler) handleEnroll(ctx context.Context, user *models.User, programID string, initialMax int) *response.Response {
+	status, err := h.programService.Enroll(ctx, user, programID, initialMax)
+	switch err {
+	case nil:
+	case errors.ErrProgramNotFound:
+		return response.Message(fmt.Sprintf("Программа %q не найдена. Список программ: /program", programID))
+	case errors.ErrInitialMaxRequired:
+		return response.Message(fmt.Sprintf("Эта программа рассчитывается от вашего максимума. Сделайте "+
+			"максимум отжиманий в одном подходе и отправьте результат командой: /program %s <максимум>", programID))
+	default:
+		return response.Message("Ошибка при записи в программу. Попробуйте позже.")
+	}
+
+	text := fmt.Sprintf("✅ Вы записаны в программу «%s»!", status.Program.Title)
+	if status.Enrollment.InitialMax > 0 {
+		text += fmt.Sprintf(" Нагрузка рассчитана от максимума %d.", status.Enrollment.InitialMax)
+	}
+
+	return response.Message(text + "\n\n" + formatProgramStatus(status))
+}
+
+// handleLeave выводит пользователя из программы
+func (h *ProgramHandler) handleLeave(ctx context.Context, user *models.User) *response.Response {
+	err := h.programService.Leave(ctx, user.ID)
+	switch err {
+	case nil:
+		return response.Message("🚪 Вы вышли из программы. Выбрать новую: /program")
+	case errors.ErrNotEnrolled:
+		return response.Message("Вы не записаны в программу. Выбрать программу: /program")
+	default:
+		return response.Message("Ошибка при выходе из программы. Попробуйте позже.")
+	}
+}
+
+// formatProgramStatus форматирует план программы на сегодня
+func formatProgramStatus(status *services.ProgramStatus) string {
+	program, enrollment := status.Program, status.Enrollment
+
+	var text string
+	switch status.Event {
+	case models.ProgramEventWeekCompleted:
+		text += fmt.Sprintf("🎉 Неделя пройдена! Переходим к неделе %d.\n", enrollment.Week+1)
+	case models.ProgramEventWeekRepeated:
+		text += fmt.Sprintf("🔁 В неделе был невыполненный день — повторяем неделю %d.\n", enrollment.Week+1)
+	case models.ProgramEventFinished:
+		return fmt.Sprintf("🏁 Программа «%s» пройдена! Проверьте свой максимум и выберите новую: /program", program.Title)
+	}
+	if enrollment.IsFinished() {
+		return fmt.Sprintf("🏁 Программа «%s» пройдена. Выбрать новую: /program", program.Title)
+	}
+
+	position := fmt.Sprintf("неделя %d из %d, день %d из %d",
+		enrollment.Week+1, len(program.Weeks), enrollment.Day+1, len(program.Weeks[enrollment.Week].Days))
+
+	// После выполнения плана программа уже перешла к следующему дню
+	if status.DoneToday {
+		text += fmt.Sprintf("✅ План программы «%s» на сегодня выполнен!\n", program.Title)
+		text += fmt.Sprintf("Следующая тренировка (%s): %s", position, joinCounts(status.Prescribed))
+		return text
+	}
+
+	text += fmt.Sprintf("📋 «%s», %s\n", program.Title, position)
+	text += "План: " + joinCounts(status.Prescribed)
+	if sum(status.Remaining) < sum(status.Prescribed) {
+		text += "\nОсталось: " + joinCounts(status.Remaining)
+	}
+	return text
+}
+
+func joinCounts(counts []int) string {
+	parts := make([]string, len(counts))
+	for i, count := range counts {
+		parts[i] = strconv.Itoa(count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sum(counts []int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
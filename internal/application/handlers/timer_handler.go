@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/scheduler"
+	"azhumania/internal/application/services"
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// timerPollInterval как часто проверяются сработавшие таймеры отдыха: напоминание
+// приходит с опозданием не больше этого интервала
+const timerPollInterval = time.Second
+
+// Аргументы команд-переключателей (/timer, /digest) для включения и выключения
+var (
+	switchOnArgs  = []string{"on", "вкл"}
+	switchOffArgs = []string{"off", "выкл"}
+)
+
+// timerPresets интервалы на кнопках /timer
+var timerPresets = []time.Duration{60 * time.Second, 90 * time.Second, 2 * time.Minute, 3 * time.Minute}
+
+// TimerHandler обрабатывает команду /timer и отправляет напоминания таймера отдыха
+type TimerHandler struct {
+	timerService *services.TimerService
+	router       *router.Router
+	logger       *zerolog.Logger
+}
+
+// NewTimerHandler создает обработчик таймера отдыха и регистрирует его команды в роутере
+func NewTimerHandler(timerService *services.TimerService, r *router.Router, logger *zerolog.Logger) *TimerHandler {
+	h := &TimerHandler{
+		timerService: timerService,
+		router:       r,
+		logger:       logger,
+	}
+	h.register()
+
+	return h
+}
+
+func (h *TimerHandler) register() {
+	h.router.Register(router.Command{
+		Names:       []string{"/timer"},
+		Buttons:     []string{"⏱ Таймер"},
+		Description: "таймер отдыха между подходами",
+		Handler: func(ctx context.Context, req *router.Request) *response.Response {
+			return h.HandleTimer(ctx, req.User, req.Args)
+		},
+	})
+}
+
+// HandleTimer обрабатывает команду /timer [секунды | on | off]
+func (h *TimerHandler) HandleTimer(ctx context.Context, user *models.User, args string) *response.Response {
+	arg := strings.ToLower(strings.TrimSpace(args))
+
+	var interval time.Duration
+	switch {
+	case arg == "":
+		return h.handleOverview(user)
+	case slices.Contains(switchOffArgs, arg):
+		interval = 0
+	case slices.Contains(switchOnArgs, arg):
+		interval = user.RestTimer
+		if interval == 0 {
+			interval = models.DefaultRestTimer
+		}
+	default:
+		seconds, err := strconv.Atoi(arg)
+		if err != nil {
+			return response.Message("Укажите интервал в секундах, например: /timer 90. Выключить: /timer off")
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	err := h.timerService.SetRestTimer(ctx, user, interval)
+	switch err {
+	case nil:
+	case errors.ErrInvalidRestTimer:
+		return response.Message(fmt.Sprintf("Интервал должен быть от %s до %s. Выключить таймер: /timer off",
+			formatInterval(models.MinRestTimer), formatInterval(models.MaxRestTimer)))
+	default:
+		return response.Message("Ошибка при сохранении таймера. Попробуйте позже.")
+	}
+
+	if interval == 0 {
+		return response.Message("⏱ Таймер отдыха выключен.")
+	}
+	return response.Message(fmt.Sprintf("⏱ Таймер отдыха: %s. После каждого подхода я напомню о следующем, "+
+		"а новый подход до напоминания запустит отсчет заново.", formatInterval(interval)))
+}
+
+// handleOverview показывает настройку таймера и кнопки выбора интервала
+func (h *TimerHandler) handleOverview(user *models.User) *response.Response {
+	text := "⏱ Таймер отдыха выключен."
+	if user.RestTimer > 0 {
+		text = fmt.Sprintf("⏱ Таймер отдыха: %s.", formatInterval(user.RestTimer))
+	}
+	text += "\n\nПосле подхода бот напомнит о следующем, когда закончится отдых. " +
+		"Свой интервал в секундах: /timer 75"
+
+	var presets []response.InlineButton
+	for _, preset := range timerPresets {
+		presets = append(presets, response.InlineButton{
+			Text: formatInterval(preset),
+			Data: fmt.Sprintf("/timer %d", int(preset/time.Second)),
+		})
+	}
+	rows := [][]response.InlineButton{presets}
+	if user.RestTimer > 0 {
+		rows = append(rows, []response.InlineButton{{Text: "🔕 Выключить", Data: "/timer " + switchOffArgs[0]}})
+	}
+
+	return response.MessageWithKeyboard(text, response.InlineKeyboard{Rows: rows})
+}
+
+// Job возвращает задачу планировщика, которая отправляет напоминания сработавших таймеров
+func (h *TimerHandler) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:    "rest_timers",
+		Every:   timerPollInterval,
+		Timeout: 10 * timerPollInterval,
+		Run: func(ctx context.Context) (*response.Response, error) {
+			timers, err := h.timerService.Due(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			resp := response.None()
+			for _, timer := range timers {
+				resp.Add(response.Text{Text: "⏱ Пора! Следующий подход", ChatID: timer.UserID})
+			}
+			return resp, nil
+		},
+	}
+}
+
+// formatInterval форматирует интервал таймера: "90 с", "2 мин", "1 ч"
+func formatInterval(interval time.Duration) string {
+	switch {
+	case interval >= time.Hour && interval%time.Hour == 0:
+		return fmt.Sprintf("%d ч", interval/time.Hour)
+	case interval >= time.Minute && interval%time.Minute == 0:
+		return fmt.Sprintf("%d мин", interval/time.Minute)
+	default:
+		return fmt.Sprintf("%d с", interval/time.Second)
+	}
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limit описывает token bucket: не больше Burst действий подряд,
+// затем одно действие каждые Every
+type Limit struct {
+	Every time.Duration
+	Burst int
+}
+
+// PerMinute возвращает лимит в n действий в минуту с запасом burst
+func PerMinute(n, burst int) Limit {
+	return Limit{Every: time.Minute / time.Duration(n), Burst: burst}
+}
+
+// PerSecond возвращает лимит в n действий в секунду с запасом burst
+func PerSecond(n, burst int) Limit {
+	return Limit{Every: time.Second / time.Duration(n), Burst: burst}
+}
+
+// Limiter ограничивает частоту действий по ключу
+type Limiter interface {
+	// Allow забирает один токен из корзины key и сообщает, разрешено ли действие
+	Allow(ctx context.Context, key string, limit Limit) (bool, error)
+}
+
+// Wait ждет, пока limiter разрешит действие по ключу key, или отмены ctx
+func Wait(ctx context.Context, limiter Limiter, key string, limit Limit) error {
+	for {
+		ok, err := limiter.Allow(ctx, key, limit)
+		if err != nil || ok {
+			return err
+		}
+
+		// За limit.Every в корзине появляется новый токен
+		select {
+		case <-time.After(limit.Every):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+var _ Limiter = &MemoryLimiter{}
+
+// sweepInterval период очистки простаивающих корзин в MemoryLimiter
+const sweepInterval = time.Minute
+
+// MemoryLimiter хранит корзины в памяти процесса. Подходит для одного экземпляра бота
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Duration // время, за которое корзина наполняется полностью
+}
+
+// NewMemoryLimiter создает новый MemoryLimiter
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow забирает один токен из корзины key
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.full = limit.Every * time.Duration(limit.Burst)
+
+	b.tokens += float64(now.Sub(b.last)) / float64(limit.Every)
+	if b.tokens > float64(limit.Burst) {
+		b.tokens = float64(limit.Burst)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, nil
+	}
+	b.tokens--
+
+	return true, nil
+}
+
+// sweep удаляет корзины, которые успели наполниться: они не отличаются от новых
+func (l *MemoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= b.full {
+			delete(l.buckets, key)
+		}
+	}
+}
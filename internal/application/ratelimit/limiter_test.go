@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock время для MemoryLimiter, которое двигает тест
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestLimiter() (*MemoryLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)}
+	limiter := NewMemoryLimiter()
+	limiter.now = clock.Now
+	return limiter, clock
+}
+
+func TestMemoryLimiterAllow(t *testing.T) {
+	limit := PerMinute(6, 2) // токен каждые 10 секунд, два подряд
+
+	tests := []struct {
+		name    string
+		advance time.Duration // пауза перед запросом
+		want    bool
+	}{
+		{"первый из запаса", 0, true},
+		{"второй из запаса", 0, true},
+		{"запас кончился", 0, false},
+		{"токен еще не появился", 9 * time.Second, false},
+		{"появился токен", time.Second, true},
+		{"токен потрачен", 0, false},
+		{"запас копится не больше burst", time.Hour, true},
+		{"второй после паузы", 0, true},
+		{"третий после паузы", 0, false},
+	}
+
+	limiter, clock := newTestLimiter()
+	for _, tt := range tests {
+		clock.Advance(tt.advance)
+		got, err := limiter.Allow(context.Background(), "key", limit)
+		if err != nil {
+			t.Fatalf("%s: Allow error = %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: Allow = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryLimiterKeysAreIndependent(t *testing.T) {
+	limiter, _ := newTestLimiter()
+	limit := Limit{Every: time.Minute, Burst: 1}
+
+	for _, key := range []string{"msg:1", "msg:2"} {
+		if ok, _ := limiter.Allow(context.Background(), key, limit); !ok {
+			t.Errorf("Allow(%q) = false, want true", key)
+		}
+	}
+	if ok, _ := limiter.Allow(context.Background(), "msg:1", limit); ok {
+		t.Error("Allow(msg:1) second time = true, want false")
+	}
+}
+
+func TestMemoryLimiterSweepsFullBuckets(t *testing.T) {
+	limiter, clock := newTestLimiter()
+	limit := Limit{Every: time.Second, Burst: 1}
+
+	limiter.Allow(context.Background(), "idle", limit)
+	clock.Advance(sweepInterval)
+	limiter.Allow(context.Background(), "active", limit)
+
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Error("idle bucket was not swept")
+	}
+	if _, ok := limiter.buckets["active"]; !ok {
+		t.Error("active bucket was swept")
+	}
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"azhumania/internal/logging"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Decision результат проверки лимита
+type Decision int
+
+const (
+	// Allowed запрос можно обрабатывать
+	Allowed Decision = iota
+	// Throttled лимит превышен, пользователя нужно предупредить
+	Throttled
+	// Silenced лимит превышен, предупреждение в этом окне уже отправлено
+	Silenced
+)
+
+// Policy применяет лимиты к сообщениям пользователей Telegram. Ключи "msg:", "expensive:" и
+// "notice:" с Telegram ID удаляются вместе с данными пользователя в redis DeleteUserData
+type Policy struct {
+	limiter   Limiter
+	message   Limit
+	expensive Limit
+	notice    Limit
+	logger    *zerolog.Logger
+}
+
+// NewPolicy создает политику ограничений. message применяется ко всем сообщениям,
+// expensive — дополнительно к тяжелым командам, предупреждение отправляется не чаще раза в noticeWindow
+func NewPolicy(limiter Limiter, message, expensive Limit, noticeWindow time.Duration, logger *zerolog.Logger) *Policy {
+	return &Policy{
+		limiter:   limiter,
+		message:   message,
+		expensive: expensive,
+		notice:    Limit{Every: noticeWindow, Burst: 1},
+		logger:    logger,
+	}
+}
+
+// Check проверяет, можно ли обработать сообщение пользователя. Nil политика разрешает все.
+// При ошибке хранилища лимитов запрос пропускается, чтобы не блокировать пользователей
+func (p *Policy) Check(ctx context.Context, telegramID int64, expensive bool) Decision {
+	if p == nil {
+		return Allowed
+	}
+
+	if !p.allow(ctx, fmt.Sprintf("msg:%d", telegramID), p.message) {
+		return p.throttled(ctx, telegramID)
+	}
+
+	if expensive && !p.allow(ctx, fmt.Sprintf("expensive:%d", telegramID), p.expensive) {
+		return p.throttled(ctx, telegramID)
+	}
+
+	return Allowed
+}
+
+// AllowExpensive проверяет только лимит тяжелых команд: для запросов, на которые нельзя
+// ответить предупреждением, например inline. Nil политика разрешает все
+func (p *Policy) AllowExpensive(ctx context.Context, telegramID int64) bool {
+	if p == nil {
+		return true
+	}
+	return p.allow(ctx, fmt.Sprintf("expensive:%d", telegramID), p.expensive)
+}
+
+// throttled решает, нужно ли отправлять предупреждение в текущем окне
+func (p *Policy) throttled(ctx context.Context, telegramID int64) Decision {
+	if p.allow(ctx, fmt.Sprintf("notice:%d", telegramID), p.notice) {
+		return Throttled
+	}
+	return Silenced
+}
+
+func (p *Policy) allow(ctx context.Context, key string, limit Limit) bool {
+	allowed, err := p.limiter.Allow(ctx, key, limit)
+	if err != nil {
+		logging.FromContext(ctx, p.logger).Error().Err(err).Str("key", key).Msg("failed to check rate limit")
+		return true
+	}
+	return allowed
+}
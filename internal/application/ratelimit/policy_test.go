@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestPolicy() (*Policy, *fakeClock) {
+	limiter, clock := newTestLimiter()
+	logger := zerolog.Nop()
+	return NewPolicy(limiter, Limit{Every: time.Second, Burst: 3}, Limit{Every: time.Minute, Burst: 1}, time.Minute, &logger), clock
+}
+
+func TestPolicyNoticeWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance time.Duration
+		want    Decision
+	}{
+		{"в пределах лимита", 0, Allowed},
+		{"в пределах лимита", 0, Allowed},
+		{"в пределах лимита", 0, Allowed},
+		{"первое превышение предупреждает", 0, Throttled},
+		{"повторное превышение молчит", 0, Silenced},
+		{"лимит восстановился", time.Second, Allowed},
+		{"превышение в том же окне молчит", 0, Silenced},
+		{"корзина наполнилась", time.Minute, Allowed},
+		{"в пределах лимита", 0, Allowed},
+		{"в пределах лимита", 0, Allowed},
+		{"новое окно предупреждает снова", 0, Throttled},
+	}
+
+	policy, clock := newTestPolicy()
+	for i, tt := range tests {
+		clock.Advance(tt.advance)
+		if got := policy.Check(context.Background(), 1, false); got != tt.want {
+			t.Errorf("step %d, %s: Check = %d, want %d", i, tt.name, got, tt.want)
+		}
+	}
+
+	if got := policy.Check(context.Background(), 2, false); got != Allowed {
+		t.Errorf("other user Check = %d, want Allowed", got)
+	}
+}
+
+func TestPolicyExpensive(t *testing.T) {
+	policy, clock := newTestPolicy()
+
+	if got := policy.Check(context.Background(), 1, true); got != Allowed {
+		t.Fatalf("first expensive Check = %d, want Allowed", got)
+	}
+	if got := policy.Check(context.Background(), 1, true); got != Throttled {
+		t.Errorf("second expensive Check = %d, want Throttled", got)
+	}
+	if got := policy.Check(context.Background(), 1, false); got != Allowed {
+		t.Errorf("regular Check after expensive limit = %d, want Allowed", got)
+	}
+	if policy.AllowExpensive(context.Background(), 1) {
+		t.Error("AllowExpensive = true with the expensive limit exhausted")
+	}
+
+	clock.Advance(time.Minute)
+	if !policy.AllowExpensive(context.Background(), 1) {
+		t.Error("AllowExpensive = false after the limit recovered")
+	}
+}
+
+// failingLimiter хранилище лимитов, которое всегда отвечает ошибкой
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, Limit) (bool, error) {
+	return false, errors.New("storage unavailable")
+}
+
+func TestPolicyAllowsOnLimiterError(t *testing.T) {
+	logger := zerolog.Nop()
+	policy := NewPolicy(failingLimiter{}, Limit{Every: time.Second, Burst: 1}, Limit{Every: time.Minute, Burst: 1}, time.Minute, &logger)
+
+	if got := policy.Check(context.Background(), 1, true); got != Allowed {
+		t.Errorf("Check with failing limiter = %d, want Allowed", got)
+	}
+
+	var nilPolicy *Policy
+	if got := nilPolicy.Check(context.Background(), 1, true); got != Allowed || !nilPolicy.AllowExpensive(context.Background(), 1) {
+		t.Errorf("nil policy Check = %d, want Allowed", got)
+	}
+}
@@ -0,0 +1,24 @@
+package response
+
+import "time"
+
+// InlineAnswer ответ на inline запрос: результаты, из которых пользователь выбирает
+// сообщение для отправки в текущий чат
+type InlineAnswer struct {
+	Results   []InlineResult
+	CacheTime time.Duration // сколько Telegram может отдавать этот ответ без запроса к боту
+	Personal  bool          // ответ зависит от пользователя, кэшировать его отдельно для каждого
+	// StartText кнопка над результатами, открывающая личный чат с ботом с параметром StartParam
+	StartText  string
+	StartParam string
+}
+
+// InlineResult один результат inline запроса: статья с текстом или фото по ссылке
+type InlineResult struct {
+	ID          string
+	Title       string
+	Description string
+	Text        string // текст сообщения, для фото — подпись
+	ParseMode   ParseMode
+	PhotoURL    string // если указан, результат отправляется как фото
+}
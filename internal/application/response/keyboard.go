@@ -0,0 +1,30 @@
+package response
+
+// Keyboard клавиатура, прикрепляемая к сообщению
+type Keyboard interface {
+	keyboard()
+}
+
+// ReplyKeyboard клавиатура под полем ввода, нажатие кнопки отправляет ее текст
+type ReplyKeyboard struct {
+	Rows [][]string
+}
+
+// InlineKeyboard кнопки под сообщением
+type InlineKeyboard struct {
+	Rows [][]InlineButton
+}
+
+// InlineButton кнопка под сообщением: Data возвращается боту при нажатии, URL открывает ссылку
+type InlineButton struct {
+	Text string
+	Data string
+	URL  string
+}
+
+// RemoveKeyboard скрывает клавиатуру под полем ввода
+type RemoveKeyboard struct{}
+
+func (ReplyKeyboard) keyboard()  {}
+func (InlineKeyboard) keyboard() {}
+func (RemoveKeyboard) keyboard() {}
@@ -0,0 +1,104 @@
+// Package response описывает ответ бота независимо от транспорта: обработчики формируют
+// список действий, а адаптер транспорта (Telegram, CLI) решает, как их выполнить
+package response
+
+// ParseMode режим форматирования текста
+type ParseMode string
+
+const (
+	ParseModePlain      ParseMode = ""
+	ParseModeHTML       ParseMode = "HTML"
+	ParseModeMarkdownV2 ParseMode = "MarkdownV2"
+)
+
+// Action одно исходящее действие бота
+type Action interface {
+	action()
+}
+
+// Text отправляет текстовое сообщение
+type Text struct {
+	Text      string
+	ParseMode ParseMode
+	Keyboard  Keyboard // nil — клавиатура не меняется
+	ChatID    int64    // получатель, 0 — чат, из которого пришло сообщение
+}
+
+// File содержимое отправляемого файла
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Photo отправляет изображение
+type Photo struct {
+	File      File
+	Caption   string
+	ParseMode ParseMode
+	Keyboard  Keyboard
+}
+
+// Document отправляет файл
+type Document struct {
+	File      File
+	Caption   string
+	ParseMode ParseMode
+	Keyboard  Keyboard
+}
+
+// Edit изменяет текст ранее отправленного сообщения.
+// MessageID 0 означает сообщение, к которому относится нажатая inline кнопка
+type Edit struct {
+	MessageID int
+	Text      string
+	ParseMode ParseMode
+	Keyboard  *InlineKeyboard
+}
+
+// CallbackAnswer отвечает на нажатие inline кнопки всплывающим уведомлением
+type CallbackAnswer struct {
+	Text      string
+	ShowAlert bool
+}
+
+func (Text) action()           {}
+func (Photo) action()          {}
+func (Document) action()       {}
+func (Edit) action()           {}
+func (CallbackAnswer) action() {}
+
+// Response список действий, которые нужно выполнить в ответ на сообщение
+type Response struct {
+	Actions []Action
+}
+
+// New создает ответ из списка действий
+func New(actions ...Action) *Response {
+	return &Response{Actions: actions}
+}
+
+// Message создает ответ из одного текстового сообщения
+func Message(text string) *Response {
+	return New(Text{Text: text})
+}
+
+// MessageWithKeyboard создает ответ из одного текстового сообщения с клавиатурой
+func MessageWithKeyboard(text string, keyboard Keyboard) *Response {
+	return New(Text{Text: text, Keyboard: keyboard})
+}
+
+// None создает пустой ответ: пользователю ничего не отправляется
+func None() *Response {
+	return &Response{}
+}
+
+// Add добавляет действия в конец ответа
+func (r *Response) Add(actions ...Action) *Response {
+	r.Actions = append(r.Actions, actions...)
+	return r
+}
+
+// Empty проверяет, что в ответе нет действий
+func (r *Response) Empty() bool {
+	return r == nil || len(r.Actions) == 0
+}
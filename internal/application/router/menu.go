@@ -0,0 +1,63 @@
+package router
+
+import (
+	"azhumania/internal/application/response"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// keyboardColumns количество кнопок в одном ряду клавиатуры
+const keyboardColumns = 2
+
+// HelpText возвращает список команд с описаниями для /help
+func (r *Router) HelpText() string {
+	var b strings.Builder
+	for _, cmd := range r.Commands() {
+		if len(cmd.Names) == 0 {
+			continue
+		}
+		b.WriteString(cmd.Names[0])
+		b.WriteString(" - ")
+		b.WriteString(cmd.Description)
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Keyboard возвращает основную клавиатуру с кнопками видимых команд
+func (r *Router) Keyboard() response.ReplyKeyboard {
+	var rows [][]string
+	var row []string
+
+	for _, cmd := range r.Commands() {
+		if len(cmd.Buttons) == 0 {
+			continue
+		}
+		row = append(row, cmd.Buttons[0])
+		if len(row) == keyboardColumns {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	return response.ReplyKeyboard{Rows: rows}
+}
+
+// BotCommands возвращает команды для меню Telegram (setMyCommands)
+func (r *Router) BotCommands() []tgbotapi.BotCommand {
+	var commands []tgbotapi.BotCommand
+	for _, cmd := range r.Commands() {
+		if len(cmd.Names) == 0 || cmd.Permission != PermissionUser {
+			continue
+		}
+		commands = append(commands, tgbotapi.BotCommand{
+			Command:     strings.TrimPrefix(cmd.Names[0], "/"),
+			Description: cmd.Description,
+		})
+	}
+	return commands
+}
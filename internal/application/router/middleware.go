@@ -0,0 +1,159 @@
+package router
+
+import (
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/application/response"
+	"azhumania/internal/domain/models"
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// errorMessage ответ пользователю при внутренней ошибке
+const errorMessage = "Произошла ошибка. Попробуйте позже."
+
+// Recover перехватывает панику в обработчике и отвечает пользователю сообщением об ошибке
+func Recover(logger *zerolog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) (resp *response.Response) {
+			defer func() {
+				if p := recover(); p != nil {
+					logging.FromContext(ctx, logger).Error().
+						Interface("panic", p).
+						Bytes("stack", debug.Stack()).
+						Str("text", req.Text).
+						Msg("panic in message handler")
+					resp = response.Message(errorMessage)
+				}
+			}()
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// Logging пишет в лог каждую обработанную команду и время ее выполнения
+func Logging(logger *zerolog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) *response.Response {
+			started := time.Now()
+			resp := next(ctx, req)
+
+			logger := logging.FromContext(ctx, logger)
+			event := logger.Debug()
+			if req.Command != nil && len(req.Command.Names) > 0 {
+				event = logger.Info().Str("command", req.Command.Names[0])
+			}
+			event.Dur("duration", time.Since(started)).Msg("message handled")
+
+			return resp
+		}
+	}
+}
+
+// Metrics считает обработанные команды и время их обработки
+func Metrics() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) *response.Response {
+			started := time.Now()
+			resp := next(ctx, req)
+
+			command := commandLabel(req)
+			metrics.CommandsHandled.WithLabelValues(command).Inc()
+			metrics.HandlerDuration.WithLabelValues(command).Observe(time.Since(started).Seconds())
+
+			return resp
+		}
+	}
+}
+
+// commandLabel возвращает имя команды для метрик. Текст пользователя в метки не попадает
+func commandLabel(req *Request) string {
+	switch {
+	case req.Command != nil && len(req.Command.Names) > 0:
+		return req.Command.Names[0]
+	case req.Command != nil:
+		return req.Command.Buttons[0]
+	case strings.HasPrefix(req.Text, "/"):
+		return "unknown"
+	default:
+		return "text"
+	}
+}
+
+// RateLimit ограничивает частоту сообщений пользователя. Тяжелые команды проверяются
+// дополнительно по отдельному лимиту. При повторном превышении в том же окне ответ пустой
+func RateLimit(policy *ratelimit.Policy) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) *response.Response {
+			if req.Message.From == nil {
+				return next(ctx, req)
+			}
+
+			expensive := req.Command != nil && req.Command.Expensive
+			switch policy.Check(ctx, req.Message.From.ID, expensive) {
+			case ratelimit.Throttled:
+				return response.Message("⏳ Слишком много сообщений. Подождите немного и попробуйте снова.")
+			case ratelimit.Silenced:
+				return response.None()
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// LoadUser загружает (или создает) пользователя, отправившего сообщение
+func LoadUser(load func(ctx context.Context, msg *tgbotapi.Message) (*models.User, error), logger *zerolog.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) *response.Response {
+			if req.Message.From == nil {
+				return response.Message("Ошибка: пустое сообщение")
+			}
+
+			user, err := load(ctx, req.Message)
+			if err != nil {
+				logging.FromContext(ctx, logger).Error().Err(err).Msg("failed to get or create user")
+				return response.Message(errorMessage)
+			}
+			req.User = user
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// Authorize отклоняет сообщения заблокированных пользователей и проверяет, что у пользователя
+// есть права на выполнение команды. Должен стоять после LoadUser
+func Authorize() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, req *Request) *response.Response {
+			if req.User != nil && req.User.IsBanned() {
+				return response.Message("⛔ Доступ к боту ограничен.")
+			}
+			if req.Command != nil && !req.Command.Permission.allows(req.User) {
+				return response.Message("⛔ Недостаточно прав для этой команды.")
+			}
+
+			return next(ctx, req)
+		}
+	}
+}
+
+// allows проверяет, достаточно ли у пользователя прав
+func (p Permission) allows(user *models.User) bool {
+	switch p {
+	case PermissionUser:
+		return user != nil
+	case PermissionAdmin:
+		return user != nil && user.IsAdmin()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+package router
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/domain/models"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// Request входящее сообщение, которое проходит через цепочку middleware
+type Request struct {
+	Message *tgbotapi.Message
+	Text    string       // текст сообщения без пробелов по краям
+	Command *Command     // найденная команда, nil для обычного текста
+	Args    string       // текст после имени команды
+	User    *models.User // заполняется middleware LoadUser
+}
+
+// HandlerFunc обрабатывает запрос и возвращает ответ
+type HandlerFunc func(ctx context.Context, req *Request) *response.Response
+
+// Middleware оборачивает обработчик дополнительной логикой
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Permission уровень доступа, необходимый для команды
+type Permission int
+
+const (
+	// PermissionUser команда доступна любому пользователю
+	PermissionUser Permission = iota
+	// PermissionAdmin команда доступна только администраторам
+	PermissionAdmin
+)
+
+// Command описывает команду бота
+type Command struct {
+	Names       []string // команды вида "/stats", первая используется в меню и справке
+	Buttons     []string // надписи кнопок клавиатуры, вызывающих команду
+	Description string   // описание для /help и меню команд Telegram
+	Handler     HandlerFunc
+	Permission  Permission
+	Expensive   bool // тяжелая команда с отдельным лимитом частоты
+	Hidden      bool // не показывать в справке, меню и клавиатуре
+}
+
+// Router находит команду по тексту сообщения и вызывает ее обработчик через цепочку middleware
+type Router struct {
+	commands    []*Command
+	byText      map[string]*Command
+	middlewares []Middleware
+	fallback    HandlerFunc
+	notFound    HandlerFunc
+	logger      *zerolog.Logger
+}
+
+// New создает пустой роутер
+func New(logger *zerolog.Logger) *Router {
+	return &Router{
+		byText: make(map[string]*Command),
+		logger: logger,
+	}
+}
+
+// Use добавляет middleware. Middleware выполняются в порядке добавления
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Register добавляет команду. Повторная регистрация имени или кнопки — ошибка программиста
+func (r *Router) Register(cmd Command) {
+	c := &cmd
+	for _, text := range append(append([]string(nil), cmd.Names...), cmd.Buttons...) {
+		if _, exists := r.byText[text]; exists {
+			panic(fmt.Sprintf("router: %q is already registered", text))
+		}
+		r.byText[text] = c
+	}
+	r.commands = append(r.commands, c)
+}
+
+// Fallback задает обработчик сообщений, которые не являются командами
+func (r *Router) Fallback(handler HandlerFunc) {
+	r.fallback = handler
+}
+
+// NotFound задает обработчик неизвестных команд
+func (r *Router) NotFound(handler HandlerFunc) {
+	r.notFound = handler
+}
+
+// Handle находит обработчик сообщения и выполняет его
+func (r *Router) Handle(ctx context.Context, msg *tgbotapi.Message) *response.Response {
+	req := &Request{
+		Message: msg,
+		Text:    strings.TrimSpace(msg.Text),
+	}
+
+	handler := r.fallback
+	if cmd, args, ok := r.match(req.Text); ok {
+		req.Command = cmd
+		req.Args = args
+		handler = cmd.Handler
+	} else if strings.HasPrefix(req.Text, "/") {
+		handler = r.notFound
+	}
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler(ctx, req)
+}
+
+// match ищет команду по тексту кнопки или по имени команды с аргументами
+func (r *Router) match(text string) (*Command, string, bool) {
+	if cmd, ok := r.byText[text]; ok {
+		return cmd, "", true
+	}
+
+	if !strings.HasPrefix(text, "/") {
+		return nil, "", false
+	}
+
+	// Аргументы могут начинаться с новой строки: "/broadcast\nтекст"
+	name, args := text, ""
+	if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+		name, args = text[:i], text[i:]
+	}
+	// В группах Telegram добавляет к команде имя бота: /stats@azhumania_bot
+	name, _, _ = strings.Cut(name, "@")
+
+	cmd, ok := r.byText[strings.ToLower(name)]
+	return cmd, strings.TrimSpace(args), ok
+}
+
+// Commands возвращает видимые команды в порядке регистрации
+func (r *Router) Commands() []*Command {
+	var commands []*Command
+	for _, cmd := range r.commands {
+		if !cmd.Hidden {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands
+}
@@ -0,0 +1,105 @@
+// Package scheduler запускает периодические фоновые задачи, которые отправляют сообщения
+// сами, а не в ответ на сообщение пользователя: напоминания, сводки
+package scheduler
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/metrics"
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Sender доставляет ответ, сформированный задачей. Получатели указываются в response.Text.ChatID
+type Sender interface {
+	Send(ctx context.Context, resp *response.Response)
+}
+
+// Job периодическая задача. Run возвращает сообщения, которые нужно отправить, или nil.
+// Сообщения, возвращенные вместе с ошибкой, тоже отправляются
+type Job struct {
+	Name    string
+	Every   time.Duration
+	Timeout time.Duration // ограничение одного запуска, 0 — Every
+	Run     func(ctx context.Context) (*response.Response, error)
+}
+
+// Scheduler запускает зарегистрированные задачи, каждую в своей горутине, чтобы медленная
+// задача не задерживала остальные и обработку обновлений
+type Scheduler struct {
+	jobs   []Job
+	logger *zerolog.Logger
+}
+
+// New создает планировщик без задач
+func New(logger *zerolog.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Register добавляет задачу. Задачи регистрируются до вызова Run
+func (s *Scheduler) Register(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Run запускает задачи и блокируется, пока не будет отменен ctx и не завершатся текущие запуски
+func (s *Scheduler) Run(ctx context.Context, sender Sender) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job, sender)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop запускает задачу раз в job.Every
+func (s *Scheduler) loop(ctx context.Context, job Job, sender Sender) {
+	ticker := time.NewTicker(job.Every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.run(ctx, job, sender)
+		}
+	}
+}
+
+// run выполняет один запуск задачи и отправляет ее сообщения
+func (s *Scheduler) run(ctx context.Context, job Job, sender Sender) {
+	logger := s.logger.With().Str("job", job.Name).Logger()
+	defer func() {
+		if p := recover(); p != nil {
+			metrics.JobRuns.WithLabelValues(job.Name, "error").Inc()
+			logger.Error().Interface("panic", p).Bytes("stack", debug.Stack()).Msg("panic in job")
+		}
+	}()
+
+	timeout := job.Timeout
+	if timeout == 0 {
+		timeout = job.Every
+	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := job.Run(runCtx)
+	if err != nil {
+		metrics.JobRuns.WithLabelValues(job.Name, "error").Inc()
+		logger.Error().Err(err).Msg("job failed")
+	} else {
+		metrics.JobRuns.WithLabelValues(job.Name, "ok").Inc()
+	}
+
+	if resp.Empty() {
+		return
+	}
+	// Задача уже забрала свои данные, поэтому сообщения отправляются и во время остановки
+	sender.Send(context.WithoutCancel(ctx), resp)
+}
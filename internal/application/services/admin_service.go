@@ -0,0 +1,158 @@
+package services
+
+import (
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// broadcastTTL время, в течение которого можно подтвердить подготовленную рассылку
+const broadcastTTL = 10 * time.Minute
+
+// pendingBroadcast рассылка, ожидающая подтверждения администратора
+type pendingBroadcast struct {
+	text    string
+	created time.Time
+}
+
+// AdminService предоставляет бизнес-логику административных команд
+type AdminService struct {
+	userRepo  repositories.UserRepository
+	adminRepo repositories.AdminRepository
+	tokenRepo repositories.TokenRepository
+	logger    *zerolog.Logger
+
+	mu      sync.Mutex
+	pending map[int64]pendingBroadcast // по Telegram ID администратора
+}
+
+// NewAdminService создает новый экземпляр AdminService
+func NewAdminService(
+	userRepo repositories.UserRepository,
+	adminRepo repositories.AdminRepository,
+	tokenRepo repositories.TokenRepository,
+	logger *zerolog.Logger,
+) *AdminService {
+	return &AdminService{
+		userRepo:  userRepo,
+		adminRepo: adminRepo,
+		tokenRepo: tokenRepo,
+		logger:    logger,
+		pending:   make(map[int64]pendingBroadcast),
+	}
+}
+
+// GetStats возвращает сводную статистику бота за сегодня
+func (s *AdminService) GetStats(ctx context.Context) (*models.AdminStats, error) {
+	today := time.Now().Truncate(24 * time.Hour)
+
+	stats, err := s.adminRepo.GetStats(ctx, today)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Msg("failed to get admin stats")
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// GetUser получает пользователя по Telegram ID
+func (s *AdminService) GetUser(ctx context.Context, telegramID int64) (*models.User, error) {
+	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
+	if err == sql.ErrNoRows {
+		return nil, errors.ErrUserNotFound
+	}
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Ban блокирует пользователя: бот перестает обрабатывать его сообщения, а токены REST API
+// отзываются. После разблокировки пользователь получает новый токен командой /token
+func (s *AdminService) Ban(ctx context.Context, adminID, telegramID int64) (*models.User, error) {
+	user, err := s.GetUser(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := user.Ban(); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to ban user")
+		return nil, err
+	}
+	if err := s.tokenRepo.DeleteByUser(ctx, user.ID); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to revoke api tokens of banned user")
+		return nil, err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("adminID", adminID).Int64("telegramID", telegramID).Msg("user banned")
+
+	return user, nil
+}
+
+// Unban снимает блокировку пользователя
+func (s *AdminService) Unban(ctx context.Context, adminID, telegramID int64) (*models.User, error) {
+	user, err := s.GetUser(ctx, telegramID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Unban()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to unban user")
+		return nil, err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("adminID", adminID).Int64("telegramID", telegramID).Msg("user unbanned")
+
+	return user, nil
+}
+
+// PrepareBroadcast сохраняет текст рассылки до подтверждения. Новая рассылка заменяет
+// неподтвержденную. Черновики хранятся в памяти процесса и теряются при перезапуске
+func (s *AdminService) PrepareBroadcast(adminID int64, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[adminID] = pendingBroadcast{text: text, created: time.Now()}
+}
+
+// ConfirmBroadcast забирает подготовленную рассылку и возвращает ее текст и получателей
+func (s *AdminService) ConfirmBroadcast(ctx context.Context, adminID int64) (string, []int64, error) {
+	s.mu.Lock()
+	broadcast, ok := s.pending[adminID]
+	delete(s.pending, adminID)
+	s.mu.Unlock()
+
+	if !ok || time.Since(broadcast.created) > broadcastTTL {
+		return "", nil, errors.ErrNoPendingBroadcast
+	}
+
+	recipients, err := s.adminRepo.ListRecipients(ctx)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Msg("failed to list broadcast recipients")
+		return "", nil, err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("adminID", adminID).Int("recipients", len(recipients)).Msg("broadcast confirmed")
+
+	return broadcast.text, recipients, nil
+}
+
+// CancelBroadcast отменяет подготовленную рассылку. Возвращает false, если отменять нечего
+func (s *AdminService) CancelBroadcast(adminID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.pending[adminID]
+	delete(s.pending, adminID)
+	return ok
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DigestService предоставляет бизнес-логику еженедельной сводки
+type DigestService struct {
+	userRepo      repositories.UserRepository
+	digestRepo    repositories.DigestRepository
+	pushupService *PushupService
+	logger        *zerolog.Logger
+}
+
+// NewDigestService создает новый экземпляр DigestService
+func NewDigestService(userRepo repositories.UserRepository, digestRepo repositories.DigestRepository, pushupService *PushupService, logger *zerolog.Logger) *DigestService {
+	return &DigestService{
+		userRepo:      userRepo,
+		digestRepo:    digestRepo,
+		pushupService: pushupService,
+		logger:        logger,
+	}
+}
+
+// SetWeeklyDigest подписывает пользователя на еженедельную сводку или отписывает от нее
+func (s *DigestService) SetWeeklyDigest(ctx context.Context, user *models.User, enabled bool) error {
+	if !user.SetWeeklyDigest(enabled) {
+		return nil
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to save weekly digest setting")
+		return err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("userID", user.ID).Bool("enabled", enabled).Msg("weekly digest changed")
+
+	return nil
+}
+
+// Current собирает сводку за текущую неделю пользователя
+func (s *DigestService) Current(ctx context.Context, user *models.User) (*models.WeeklyDigest, error) {
+	return s.build(ctx, user, models.WeekOf(user.Today(), user.WeekStart).From)
+}
+
+// Due собирает сводки, которые пора отправить в момент now. Каждая неделя отмечается
+// отправленной до сборки сводки, поэтому пользователь не получит ее дважды, даже если
+// задача запущена в нескольких экземплярах бота
+func (s *DigestService) Due(ctx context.Context, now time.Time) ([]*models.WeeklyDigest, error) {
+	users, err := s.userRepo.ListDigestSubscribers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var digests []*models.WeeklyDigest
+	for _, user := range users {
+		weekStart, due := models.DigestWeek(now, user.Location(), user.WeekStart)
+		if !due {
+			continue
+		}
+
+		marked, err := s.digestRepo.MarkSent(ctx, user.ID, weekStart)
+		if err != nil {
+			return digests, err
+		}
+		if !marked {
+			continue
+		}
+
+		digest, err := s.build(ctx, user, weekStart)
+		if err != nil {
+			// Неделя уже отмечена: повторная попытка могла бы прислать сводку дважды
+			logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Time("weekStart", weekStart).Msg("failed to build weekly digest")
+			continue
+		}
+		digests = append(digests, digest)
+	}
+
+	return digests, nil
+}
+
+// build собирает сводку за неделю weekStart
+func (s *DigestService) build(ctx context.Context, user *models.User, weekStart time.Time) (*models.WeeklyDigest, error) {
+	// Сессии берутся с запасом в день с каждой стороны, лишние отбрасывает NewWeeklyDigest
+	sessions, err := s.pushupService.GetHistory(ctx, user.ID, weekStart.AddDate(0, 0, -8), weekStart.AddDate(0, 0, 8))
+	if err != nil {
+		return nil, err
+	}
+	digest := models.NewWeeklyDigest(user.ID, weekStart, sessions, user.DailyGoal, user.Location())
+
+	records, err := s.pushupService.GetRecords(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	// Серия продолжается, если последняя тренировка была в один из двух последних дней недели
+	if !records.Streak.AchievedOn.Before(weekStart.AddDate(0, 0, 5)) {
+		digest.Streak = records.Streak.Value
+	}
+
+	return digest, nil
+}
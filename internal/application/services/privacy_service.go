@@ -0,0 +1,49 @@
+package services
+
+import (
+	"azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// PrivacyService предоставляет просмотр и удаление данных пользователя
+type PrivacyService struct {
+	privacyRepo repositories.PrivacyRepository
+	logger      *zerolog.Logger
+}
+
+// NewPrivacyService создает новый экземпляр PrivacyService
+func NewPrivacyService(privacyRepo repositories.PrivacyRepository, logger *zerolog.Logger) *PrivacyService {
+	return &PrivacyService{
+		privacyRepo: privacyRepo,
+		logger:      logger,
+	}
+}
+
+// Summary возвращает, какие данные пользователя хранятся
+func (s *PrivacyService) Summary(ctx context.Context, userID int64) (*models.UserDataSummary, error) {
+	return s.privacyRepo.Summary(ctx, userID)
+}
+
+// DeleteAccount удаляет пользователя и все его данные. Удаление записывается в журнал
+// account_deletions и в лог
+func (s *PrivacyService) DeleteAccount(ctx context.Context, user *models.User) (*models.UserDataSummary, error) {
+	deleted, err := s.privacyRepo.DeleteUser(ctx, user.ID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to delete account")
+		return nil, err
+	}
+
+	logging.FromContext(ctx, s.logger).Info().Int64("userID", user.ID).
+		Int("approaches", deleted.Approaches).
+		Int("apiTokens", deleted.APITokens).
+		Int("personalRecords", deleted.PersonalRecords).
+		Int("programEnrollments", deleted.ProgramEnrollments).
+		Int("weeklyDigests", deleted.WeeklyDigests).
+		Msg("account deleted")
+
+	return deleted, nil
+}
@@ -0,0 +1,43 @@
+package services
+
+import "azhumania/internal/domain/models"
+
+// programWeek сокращает запись недели программы
+func programWeek(sets ...[]int) models.ProgramWeek {
+	week := models.ProgramWeek{}
+	for _, s := range sets {
+		week.Days = append(week.Days, models.ProgramDay{Sets: s})
+	}
+	return week
+}
+
+// DefaultPrograms встроенные программы тренировок
+func DefaultPrograms() []*models.Program {
+	return []*models.Program{
+		{
+			ID:          "hundred",
+			Title:       "100 отжиманий за 6 недель",
+			Description: "3 тренировки в неделю по 5 подходов. Нагрузка считается от вашего максимума в одном подходе",
+			Scaled:      true,
+			Weeks: []models.ProgramWeek{
+				programWeek([]int{50, 60, 40, 40, 60}, []int{55, 65, 45, 45, 65}, []int{60, 70, 50, 50, 70}),
+				programWeek([]int{65, 75, 55, 55, 75}, []int{70, 80, 60, 60, 80}, []int{75, 85, 65, 65, 85}),
+				programWeek([]int{80, 95, 70, 70, 95}, []int{90, 100, 75, 75, 100}, []int{95, 110, 80, 80, 110}),
+				programWeek([]int{100, 115, 85, 85, 120}, []int{110, 125, 90, 90, 130}, []int{115, 130, 95, 95, 140}),
+				programWeek([]int{125, 140, 100, 100, 150}, []int{130, 150, 110, 110, 160}, []int{140, 160, 115, 115, 170}),
+				programWeek([]int{150, 170, 125, 125, 180}, []int{160, 180, 130, 130, 190}, []int{170, 190, 140, 140, 200}),
+			},
+		},
+		{
+			ID:          "ladder",
+			Title:       "Лесенка к 50",
+			Description: "5 тренировок в неделю по 4 подхода, за 4 недели до 50+ отжиманий в день",
+			Weeks: []models.ProgramWeek{
+				programWeek([]int{10, 8, 8, 6}, []int{10, 8, 8, 6}, []int{10, 8, 8, 6}, []int{10, 8, 8, 6}, []int{10, 8, 8, 6}),
+				programWeek([]int{12, 10, 10, 8}, []int{12, 10, 10, 8}, []int{12, 10, 10, 8}, []int{12, 10, 10, 8}, []int{12, 10, 10, 8}),
+				programWeek([]int{14, 12, 12, 10}, []int{14, 12, 12, 10}, []int{14, 12, 12, 10}, []int{14, 12, 12, 10}, []int{14, 12, 12, 10}),
+				programWeek([]int{15, 15, 12, 12}, []int{15, 15, 12, 12}, []int{15, 15, 12, 12}, []int{15, 15, 12, 12}, []int{15, 15, 12, 12}),
+			},
+		},
+	}
+}
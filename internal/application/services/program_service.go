@@ -0,0 +1,187 @@
+package services
+
+import (
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ProgramStatus состояние программы пользователя на сегодня
+type ProgramStatus struct {
+	Program    *models.Program
+	Enrollment *models.ProgramEnrollment
+	Prescribed []int               // подходы текущего дня программы
+	Remaining  []int               // что осталось сделать сегодня, пусто если план выполнен
+	DoneToday  bool                // план на сегодня выполнен, Prescribed относится к следующему дню
+	Event      models.ProgramEvent // что изменилось в программе при последнем обновлении
+}
+
+// ProgramService предоставляет бизнес-логику программ тренировок
+type ProgramService struct {
+	programRepo   repositories.ProgramRepository
+	pushupService *PushupService
+	programs      []*models.Program
+	logger        *zerolog.Logger
+}
+
+// NewProgramService создает новый экземпляр ProgramService
+func NewProgramService(programRepo repositories.ProgramRepository, pushupService *PushupService, programs []*models.Program, logger *zerolog.Logger) *ProgramService {
+	return &ProgramService{
+		programRepo:   programRepo,
+		pushupService: pushupService,
+		programs:      programs,
+		logger:        logger,
+	}
+}
+
+// Programs возвращает доступные программы
+func (s *ProgramService) Programs() []*models.Program {
+	return s.programs
+}
+
+// Program находит программу по ID
+func (s *ProgramService) Program(id string) (*models.Program, bool) {
+	for _, program := range s.programs {
+		if program.ID == id {
+			return program, true
+		}
+	}
+	return nil, false
+}
+
+// Enroll записывает пользователя в программу, заменяя текущую. Для программ с масштабированием
+// нужен максимум в одном подходе: если initialMax не указан, берется личный рекорд подхода
+func (s *ProgramService) Enroll(ctx context.Context, user *models.User, programID string, initialMax int) (*ProgramStatus, error) {
+	program, ok := s.Program(programID)
+	if !ok {
+		return nil, errors.ErrProgramNotFound
+	}
+
+	if program.Scaled && initialMax <= 0 {
+		records, err := s.pushupService.GetRecords(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		initialMax = records.Records[models.RecordMaxSet].Value
+		if initialMax <= 0 {
+			return nil, errors.ErrInitialMaxRequired
+		}
+	}
+
+	enrollment := models.NewProgramEnrollment(user.ID, program, initialMax)
+	if err := s.programRepo.SaveEnrollment(ctx, enrollment); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Str("program", programID).Msg("failed to enroll in program")
+		return nil, err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("userID", user.ID).Str("program", programID).Int("initialMax", initialMax).Msg("enrolled in program")
+
+	return s.status(ctx, user, program, enrollment, models.ProgramEventNone)
+}
+
+// Leave выводит пользователя из программы
+func (s *ProgramService) Leave(ctx context.Context, userID int64) error {
+	deleted, err := s.programRepo.DeleteEnrollment(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to leave program")
+		return err
+	}
+	if !deleted {
+		return errors.ErrNotEnrolled
+	}
+
+	return nil
+}
+
+// Today возвращает состояние программы на сегодняшний день пользователя, nil если он
+// не записан в программу
+func (s *ProgramService) Today(ctx context.Context, user *models.User) (*ProgramStatus, error) {
+	program, enrollment, err := s.enrollment(ctx, user.ID)
+	if err != nil || enrollment == nil {
+		return nil, err
+	}
+
+	today := user.Today()
+	event := enrollment.Sync(program, today)
+	if event != models.ProgramEventNone {
+		if err := s.programRepo.SaveEnrollment(ctx, enrollment); err != nil {
+			logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to save program enrollment")
+			return nil, err
+		}
+	}
+
+	return s.status(ctx, user, program, enrollment, event)
+}
+
+// RecordProgress учитывает сохраненную сессию в программе пользователя и возвращает
+// новое состояние, nil если пользователь не записан в программу
+func (s *ProgramService) RecordProgress(ctx context.Context, user *models.User, session *models.PushupSession) (*ProgramStatus, error) {
+	program, enrollment, err := s.enrollment(ctx, session.UserID)
+	if err != nil || enrollment == nil || enrollment.IsFinished() {
+		return nil, err
+	}
+
+	today := user.Today()
+	event := enrollment.Record(program, today, session.GetTotalCount())
+	if err := s.programRepo.SaveEnrollment(ctx, enrollment); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", session.UserID).Msg("failed to save program enrollment")
+		return nil, err
+	}
+	if event != models.ProgramEventNone {
+		logging.FromContext(ctx, s.logger).Info().Int64("userID", session.UserID).Str("program", program.ID).
+			Int("week", enrollment.Week).Int("day", enrollment.Day).Int("event", int(event)).Msg("program progressed")
+	}
+
+	return s.newStatus(program, enrollment, event, session.GetTotalCount(), today), nil
+}
+
+// enrollment получает участие пользователя и его программу
+func (s *ProgramService) enrollment(ctx context.Context, userID int64) (*models.Program, *models.ProgramEnrollment, error) {
+	enrollment, err := s.programRepo.GetEnrollment(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get program enrollment")
+		return nil, nil, err
+	}
+	if enrollment == nil {
+		return nil, nil, nil
+	}
+
+	program, ok := s.Program(enrollment.ProgramID)
+	if !ok {
+		// Программу убрали из кода: считаем, что пользователь не записан
+		logging.FromContext(ctx, s.logger).Warn().Int64("userID", userID).Str("program", enrollment.ProgramID).Msg("unknown program in enrollment")
+		return nil, nil, nil
+	}
+
+	return program, enrollment, nil
+}
+
+// status собирает состояние программы по сегодняшней сессии пользователя
+func (s *ProgramService) status(ctx context.Context, user *models.User, program *models.Program, enrollment *models.ProgramEnrollment, event models.ProgramEvent) (*ProgramStatus, error) {
+	session, err := s.pushupService.GetTodayStats(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.newStatus(program, enrollment, event, session.GetTotalCount(), session.Date), nil
+}
+
+func (s *ProgramService) newStatus(program *models.Program, enrollment *models.ProgramEnrollment, event models.ProgramEvent, dayTotal int, today time.Time) *ProgramStatus {
+	status := &ProgramStatus{
+		Program:    program,
+		Enrollment: enrollment,
+		Prescribed: enrollment.Prescription(program),
+		DoneToday:  enrollment.CompletedToday(today),
+		Event:      event,
+	}
+	if !status.DoneToday {
+		// Подходы, сделанные до начала дня программы, тоже идут в зачет: план считается за весь день
+		status.Remaining = models.Remaining(status.Prescribed, dayTotal)
+	}
+
+	return status
+}
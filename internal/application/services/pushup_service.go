@@ -1,9 +1,14 @@
 package services
 
 import (
+	"azhumania/internal/domain/errors"
 	"azhumania/internal/domain/models"
 	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
 	"context"
+	stderrors "errors"
+	"sort"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -12,80 +17,205 @@ import (
 // PushupService предоставляет бизнес-логику для работы с отжиманиями
 type PushupService struct {
 	pushupRepo repositories.PushupRepository
+	recordRepo repositories.RecordRepository
 	logger     *zerolog.Logger
 }
 
 // NewPushupService создает новый экземпляр PushupService
-func NewPushupService(pushupRepo repositories.PushupRepository, logger *zerolog.Logger) *PushupService {
+func NewPushupService(pushupRepo repositories.PushupRepository, recordRepo repositories.RecordRepository, logger *zerolog.Logger) *PushupService {
 	return &PushupService{
 		pushupRepo: pushupRepo,
+		recordRepo: recordRepo,
 		logger:     logger,
 	}
 }
 
-// AddPushupApproach добавляет новый подход отжиманий
-func (s *PushupService) AddPushupApproach(ctx context.Context, userID int64, count int) (*models.PushupSession, error) {
+// AddPushupApproach добавляет новый подход отжиманий и возвращает побитые им личные рекорды
+func (s *PushupService) AddPushupApproach(ctx context.Context, user *models.User, count int) (*models.PushupSession, []models.BrokenRecord, error) {
+	session, records, err := s.AddPushupApproaches(ctx, user, []int{count})
+
+	// Для одного подхода номер в ошибке не нужен
+	var approachErr *errors.ApproachError
+	if stderrors.As(err, &approachErr) {
+		return nil, nil, approachErr.Err
+	}
+
+	return session, records, err
+}
+
+// AddPushupApproaches добавляет несколько подходов одним сохранением сессии: либо записываются
+// все, либо ни одного. Ошибка проверки возвращается как *errors.ApproachError.
+// Побитые личные рекорды возвращаются вместе с сессией; ошибка при их обновлении
+// не отменяет сохранение подходов. Сессия датируется сегодняшним днем по часовому поясу пользователя
+func (s *PushupService) AddPushupApproaches(ctx context.Context, user *models.User, counts []int) (*models.PushupSession, []models.BrokenRecord, error) {
+	userID := user.ID
+
 	// Получаем или создаем сессию за сегодня
-	session, err := s.getOrCreateTodaySession(ctx, userID)
+	session, err := s.getOrCreateTodaySession(ctx, user)
 	if err != nil {
-		s.logger.Error().Err(err).Int64("userID", userID).Msg("failed to get today session")
-		return nil, err
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get today session")
+		return nil, nil, err
 	}
 
-	// Добавляем подход
-	if err := session.AddApproach(count); err != nil {
-		s.logger.Error().Err(err).Int64("userID", userID).Msg("failed to add pushup approach")
-		return nil, err
+	// Добавляем подходы
+	if err := session.AddApproaches(counts...); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to add pushup approaches")
+		return nil, nil, err
 	}
 
+	// Рекорды читаем до сохранения: если их придется вычислять по истории,
+	// новые подходы не должны в нее попасть
+	records, recordsErr := s.GetRecords(ctx, user)
+
 	// Сохраняем сессию
 	if err := s.pushupRepo.SaveSession(ctx, session); err != nil {
-		s.logger.Error().Err(err).Int64("userID", userID).Msg("failed to save pushup session")
-		return nil, err
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to save pushup session")
+		return nil, nil, err
 	}
 
-	return session, nil
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	metrics.ApproachesLogged.Add(float64(len(counts)))
+	metrics.PushupsLogged.Add(float64(total))
+
+	if recordsErr != nil {
+		return session, nil, nil
+	}
+	broken := records.Apply(session.Date, user.WeekStart, counts, session.GetTotalCount())
+	if err := s.recordRepo.SaveRecords(ctx, records); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to save personal records")
+		return session, nil, nil
+	}
+
+	return session, broken, nil
 }
 
-// GetTodayStats получает статистику за сегодня
-func (s *PushupService) GetTodayStats(ctx context.Context, userID int64) (*models.PushupSession, error) {
-	session, err := s.pushupRepo.GetTodaySession(ctx, userID)
+// GetRecords получает личные рекорды пользователя. Если их еще нет, вычисляет по истории
+// и сохраняет, чтобы следующие проверки обходились одним запросом
+func (s *PushupService) GetRecords(ctx context.Context, user *models.User) (*models.PersonalRecords, error) {
+	userID := user.ID
+	records, err := s.recordRepo.GetRecords(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get personal records")
+		return nil, err
+	}
+	if records != nil {
+		return records, nil
+	}
+
+	history, err := s.pushupRepo.GetSessionsByDateRange(ctx, userID, time.Time{}, time.Now().AddDate(0, 0, 1))
 	if err != nil {
-		s.logger.Error().Err(err).Int64("userID", userID).Msg("failed to get today session")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get history for personal records")
 		return nil, err
 	}
 
+	records = models.PersonalRecordsFromHistory(userID, user.WeekStart, history)
+	if len(records.Records) == 0 {
+		return records, nil
+	}
+	if err := s.recordRepo.SaveRecords(ctx, records); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to save personal records")
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// GetTodayStats получает статистику за сегодняшний день пользователя
+func (s *PushupService) GetTodayStats(ctx context.Context, user *models.User) (*models.PushupSession, error) {
+	return s.getOrCreateTodaySession(ctx, user)
+}
+
+// GetDayStats получает сессию за день date, пустую если в этот день подходов не было
+func (s *PushupService) GetDayStats(ctx context.Context, user *models.User, date time.Time) (*models.PushupSession, error) {
+	if date.Equal(user.Today()) {
+		return s.GetTodayStats(ctx, user)
+	}
+	userID := user.ID
+
+	session, err := s.pushupRepo.GetSession(ctx, userID, date)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Time("date", date).Msg("failed to get day session")
+		return nil, err
+	}
 	if session == nil {
-		// Создаем пустую сессию для сегодня
-		today := time.Now().Truncate(24 * time.Hour)
-		session = models.NewPushupSession(userID, today)
+		session = models.NewPushupSession(userID, date)
 	}
 
 	return session, nil
 }
 
-// GetWeeklyStats получает статистику за неделю
-func (s *PushupService) GetWeeklyStats(ctx context.Context, userID int64) (*models.WeeklyStats, error) {
-	return s.pushupRepo.GetWeeklyStats(ctx, userID)
+// GetStats считает статистику за период
+func (s *PushupService) GetStats(ctx context.Context, userID int64, period models.Period) (*models.PeriodStats, error) {
+	sessions, err := s.GetHistory(ctx, userID, period.From, period.To)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewPeriodStats(userID, period, sessions), nil
+}
+
+// GetWeeklyStats получает статистику за текущую неделю пользователя с первым днем из его настройки
+func (s *PushupService) GetWeeklyStats(ctx context.Context, user *models.User) (*models.PeriodStats, error) {
+	return s.GetStats(ctx, user.ID, models.WeekOf(user.Today(), user.WeekStart))
+}
+
+// GetMonthlyStats получает статистику за текущий календарный месяц пользователя и текущую серию
+func (s *PushupService) GetMonthlyStats(ctx context.Context, user *models.User) (*models.MonthlyStats, error) {
+	today := user.Today()
+	period, err := s.GetStats(ctx, user.ID, models.MonthOf(today))
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.GetRecords(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := models.NewMonthlyStats(user.ID, period.Period.From)
+	stats.TotalCount = period.TotalCount
+	stats.TrainingDays = period.TrainingDays
+	stats.AveragePerDay = period.AveragePerDay
+	stats.BestDay = period.BestDay
+	stats.BestDayDate = period.BestDayDate
+	// Серия еще не прервана, если последний день серии — сегодня или вчера
+	if !records.Streak.AchievedOn.Before(today.AddDate(0, 0, -1)) {
+		stats.Streak = records.Streak.Value
+	}
+
+	return stats, nil
 }
 
-// GetMonthlyStats получает статистику за месяц
-func (s *PushupService) GetMonthlyStats(ctx context.Context, userID int64) (*models.MonthlyStats, error) {
-	return s.pushupRepo.GetMonthlyStats(ctx, userID)
+// GetHistory получает сессии за период, отсортированные по дате
+func (s *PushupService) GetHistory(ctx context.Context, userID int64, from, to time.Time) ([]*models.PushupSession, error) {
+	sessions, err := s.pushupRepo.GetSessionsByDateRange(ctx, userID, from, to)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get sessions by date range")
+		return nil, err
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Date.Before(sessions[j].Date)
+	})
+
+	return sessions, nil
 }
 
-// getOrCreateTodaySession получает или создает сессию за сегодня
-func (s *PushupService) getOrCreateTodaySession(ctx context.Context, userID int64) (*models.PushupSession, error) {
-	session, err := s.pushupRepo.GetTodaySession(ctx, userID)
+// getOrCreateTodaySession получает или создает сессию за сегодняшний день пользователя. Дата
+// сессии — день по его часовому поясу, чтобы подход в 23:00 не попал в завтрашний день по UTC
+func (s *PushupService) getOrCreateTodaySession(ctx context.Context, user *models.User) (*models.PushupSession, error) {
+	today := user.Today()
+	session, err := s.pushupRepo.GetTodaySession(ctx, user.ID, today)
 	if err != nil {
-		s.logger.Error().Err(err).Int64("userID", userID).Msg("failed to get today session")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to get today session")
 		return nil, err
 	}
 
 	if session == nil {
 		// Создаем новую сессию за сегодня
-		today := time.Now().Truncate(24 * time.Hour)
-		session = models.NewPushupSession(userID, today)
+		session = models.NewPushupSession(user.ID, today)
 	}
 
 	return session, nil
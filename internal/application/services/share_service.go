@@ -0,0 +1,66 @@
+package services
+
+import (
+	"azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// chartTTL сколько хранится картинка графика: Telegram скачивает ее сразу после ответа,
+// а ссылка нужна, пока пользователь выбирает результат
+const chartTTL = 10 * time.Minute
+
+// ShareStats статистика пользователя, которой можно поделиться в другом чате
+type ShareStats struct {
+	// Week отжимания по дням текущей недели с первого дня из настройки пользователя. Из нее
+	// собираются и текст «Моя неделя», и график, чтобы они не расходились
+	Week *models.WeeklyDigest
+	// Today сессия за сегодняшний день пользователя по его часовому поясу — тот же день,
+	// что выделен на графике недели
+	Today *models.PushupSession
+}
+
+// ShareService собирает статистику для inline режима и хранит картинки графиков
+type ShareService struct {
+	chartRepo     repositories.ChartRepository
+	pushupService *PushupService
+	digestService *DigestService
+	logger        *zerolog.Logger
+}
+
+// NewShareService создает новый экземпляр ShareService
+func NewShareService(chartRepo repositories.ChartRepository, pushupService *PushupService, digestService *DigestService, logger *zerolog.Logger) *ShareService {
+	return &ShareService{
+		chartRepo:     chartRepo,
+		pushupService: pushupService,
+		digestService: digestService,
+		logger:        logger,
+	}
+}
+
+// Stats собирает статистику за неделю и за сегодня
+func (s *ShareService) Stats(ctx context.Context, user *models.User) (*ShareStats, error) {
+	week, err := s.digestService.Current(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	today, err := s.pushupService.GetTodayStats(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShareStats{Week: week, Today: today}, nil
+}
+
+// SaveChart сохраняет картинку графика и возвращает ее ID для ссылки
+func (s *ShareService) SaveChart(ctx context.Context, data []byte) (string, error) {
+	return s.chartRepo.Save(ctx, data, chartTTL)
+}
+
+// Chart получает картинку графика, nil если ссылка устарела
+func (s *ShareService) Chart(ctx context.Context, id string) ([]byte, error) {
+	return s.chartRepo.Get(ctx, id)
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// timerBatch сколько сработавших таймеров забирается за один проход планировщика
+	timerBatch = 100
+	// staleRestTimer таймеры, просроченные дольше этого, например пока бот был остановлен,
+	// не отправляются: напоминание о подходе через полчаса уже бесполезно
+	staleRestTimer = 5 * time.Minute
+)
+
+// TimerService предоставляет бизнес-логику таймера отдыха между подходами
+type TimerService struct {
+	timerRepo repositories.TimerRepository
+	userRepo  repositories.UserRepository
+	logger    *zerolog.Logger
+}
+
+// NewTimerService создает новый экземпляр TimerService
+func NewTimerService(timerRepo repositories.TimerRepository, userRepo repositories.UserRepository, logger *zerolog.Logger) *TimerService {
+	return &TimerService{
+		timerRepo: timerRepo,
+		userRepo:  userRepo,
+		logger:    logger,
+	}
+}
+
+// SetRestTimer меняет интервал таймера отдыха пользователя, 0 выключает таймер
+func (s *TimerService) SetRestTimer(ctx context.Context, user *models.User, interval time.Duration) error {
+	changed, err := user.SetRestTimer(interval)
+	if err != nil || !changed {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to save rest timer setting")
+		return err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("userID", user.ID).Dur("interval", interval).Msg("rest timer changed")
+
+	// Уже запущенный таймер не должен сработать после выключения
+	if interval == 0 {
+		return s.timerRepo.Cancel(ctx, user.ID)
+	}
+	return nil
+}
+
+// Start запускает таймер отдыха после подхода, заменяя предыдущий. Ничего не делает,
+// если пользователь не включал таймер
+func (s *TimerService) Start(ctx context.Context, user *models.User) error {
+	if user.RestTimer == 0 {
+		return nil
+	}
+
+	return s.timerRepo.Schedule(ctx, models.NewRestTimer(user, time.Now()))
+}
+
+// Due забирает сработавшие таймеры. Давно просроченные таймеры отбрасываются
+func (s *TimerService) Due(ctx context.Context) ([]models.RestTimer, error) {
+	now := time.Now()
+	timers, err := s.timerRepo.ClaimDue(ctx, now, timerBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	due := timers[:0]
+	for _, timer := range timers {
+		if now.Sub(timer.DueAt) > staleRestTimer {
+			logging.FromContext(ctx, s.logger).Warn().Int64("userID", timer.UserID).Time("dueAt", timer.DueAt).Msg("dropped stale rest timer")
+			continue
+		}
+		due = append(due, timer)
+	}
+
+	return due, nil
+}
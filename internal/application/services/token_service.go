@@ -0,0 +1,63 @@
+package services
+
+import (
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/rs/zerolog"
+)
+
+// tokenBytes длина токена API в байтах до кодирования
+const tokenBytes = 32
+
+// TokenService выдает и проверяет токены доступа к REST API
+type TokenService struct {
+	tokenRepo repositories.TokenRepository
+	logger    *zerolog.Logger
+}
+
+// NewTokenService создает новый экземпляр TokenService
+func NewTokenService(tokenRepo repositories.TokenRepository, logger *zerolog.Logger) *TokenService {
+	return &TokenService{
+		tokenRepo: tokenRepo,
+		logger:    logger,
+	}
+}
+
+// IssueToken выдает пользователю новый токен. Предыдущие токены перестают действовать.
+// Токен возвращается только один раз, в базе хранится его хэш
+func (s *TokenService) IssueToken(ctx context.Context, userID int64) (string, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to generate api token")
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+
+	if err := s.tokenRepo.Replace(ctx, userID, hashToken(token)); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("userID", userID).Msg("failed to store api token")
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Authenticate возвращает ID пользователя, которому принадлежит токен
+func (s *TokenService) Authenticate(ctx context.Context, token string) (int64, error) {
+	if token == "" {
+		return 0, errors.ErrInvalidToken
+	}
+
+	return s.tokenRepo.GetUserID(ctx, hashToken(token))
+}
+
+// hashToken возвращает SHA-256 хэш токена в hex
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
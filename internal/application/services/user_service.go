@@ -4,8 +4,10 @@ import (
 	"azhumania/internal/domain/errors"
 	"azhumania/internal/domain/models"
 	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -13,13 +15,20 @@ import (
 // UserService предоставляет бизнес-логику для работы с пользователями
 type UserService struct {
 	userRepo repositories.UserRepository
+	admins   map[int64]bool // Telegram ID администраторов из конфигурации
 	logger   *zerolog.Logger
 }
 
-// NewUserService создает новый экземпляр UserService
-func NewUserService(userRepo repositories.UserRepository, logger *zerolog.Logger) *UserService {
+// NewUserService создает новый экземпляр UserService. adminIDs — Telegram ID администраторов
+func NewUserService(userRepo repositories.UserRepository, adminIDs []int64, logger *zerolog.Logger) *UserService {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+
 	return &UserService{
 		userRepo: userRepo,
+		admins:   admins,
 		logger:   logger,
 	}
 }
@@ -29,7 +38,7 @@ func (s *UserService) GetOrCreateUser(ctx context.Context, telegramID int64, pho
 	// Проверяем существование пользователя
 	exists, err := s.userRepo.Exists(ctx, telegramID)
 	if err != nil {
-		s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to check user existence")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to check user existence")
 		return nil, err
 	}
 
@@ -37,7 +46,14 @@ func (s *UserService) GetOrCreateUser(ctx context.Context, telegramID int64, pho
 		// Получаем существующего пользователя
 		user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
 		if err != nil {
-			s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
+			logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
+			return nil, err
+		}
+		if err := s.syncRole(ctx, user); err != nil {
+			return nil, err
+		}
+		// Пользователь написал боту, значит снова получает сообщения
+		if err := s.setInactive(ctx, user, false); err != nil {
 			return nil, err
 		}
 		return user, nil
@@ -45,13 +61,14 @@ func (s *UserService) GetOrCreateUser(ctx context.Context, telegramID int64, pho
 
 	// Создаем нового пользователя
 	user := models.NewUser(phone, nickname, telegramID)
+	user.SetRole(s.roleFor(telegramID))
 	if err := user.IsValid(); err != nil {
-		s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to validate user")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to validate user")
 		return nil, err
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
-		s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to create user")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to create user")
 		return nil, err
 	}
 
@@ -63,10 +80,10 @@ func (s *UserService) GetUser(ctx context.Context, telegramID int64) (*models.Us
 	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("user not found")
+			logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("user not found")
 			return nil, errors.ErrUserNotFound
 		}
-		s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
 		return nil, err
 	}
 	return user, nil
@@ -76,10 +93,108 @@ func (s *UserService) GetUser(ctx context.Context, telegramID int64) (*models.Us
 func (s *UserService) UpdateUserNickname(ctx context.Context, telegramID int64, nickname string) error {
 	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
 	if err != nil {
-		s.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
 		return err
 	}
 
 	user.UpdateNickname(nickname)
 	return s.userRepo.Update(ctx, user)
 }
+
+// SetTimezone меняет часовой пояс пользователя
+func (s *UserService) SetTimezone(ctx context.Context, user *models.User, timezone string) error {
+	changed, err := user.SetTimezone(timezone)
+	if err != nil || !changed {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", user.TelegramID).Msg("failed to update user timezone")
+		return err
+	}
+
+	return nil
+}
+
+// SetDailyGoal меняет дневную цель пользователя, 0 убирает цель
+func (s *UserService) SetDailyGoal(ctx context.Context, user *models.User, goal int) error {
+	changed, err := user.SetDailyGoal(goal)
+	if err != nil || !changed {
+		return err
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", user.TelegramID).Msg("failed to update user daily goal")
+		return err
+	}
+
+	return nil
+}
+
+// SetWeekStart меняет первый день недели в статистике пользователя
+func (s *UserService) SetWeekStart(ctx context.Context, user *models.User, day time.Weekday) error {
+	if !user.SetWeekStart(day) {
+		return nil
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", user.TelegramID).Msg("failed to update user week start")
+		return err
+	}
+
+	return nil
+}
+
+// MarkInactive отмечает, что Telegram не доставляет сообщения пользователю: он заблокировал
+// бота или удалил аккаунт. Отметка снимается, когда пользователь снова пишет боту
+func (s *UserService) MarkInactive(ctx context.Context, telegramID int64) error {
+	user, err := s.userRepo.GetByTelegramID(ctx, telegramID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user by telegramID")
+		return err
+	}
+
+	return s.setInactive(ctx, user, true)
+}
+
+// setInactive сохраняет отметку о доставке сообщений, если она изменилась
+func (s *UserService) setInactive(ctx context.Context, user *models.User, inactive bool) error {
+	if !user.SetInactive(inactive) {
+		return nil
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", user.TelegramID).Msg("failed to update user activity")
+		return err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("telegramID", user.TelegramID).Bool("inactive", inactive).Msg("user activity changed")
+
+	return nil
+}
+
+// roleFor возвращает роль пользователя по списку администраторов из конфигурации
+func (s *UserService) roleFor(telegramID int64) models.Role {
+	if s.admins[telegramID] {
+		return models.RoleAdmin
+	}
+	return models.RoleUser
+}
+
+// syncRole приводит сохраненную роль к конфигурации: назначает новых администраторов
+// и снимает роль с тех, кого убрали из списка
+func (s *UserService) syncRole(ctx context.Context, user *models.User) error {
+	if !user.SetRole(s.roleFor(user.TelegramID)) {
+		return nil
+	}
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logging.FromContext(ctx, s.logger).Error().Err(err).Int64("telegramID", user.TelegramID).Msg("failed to update user role")
+		return err
+	}
+	logging.FromContext(ctx, s.logger).Info().Int64("telegramID", user.TelegramID).Str("role", string(user.Role)).Msg("user role changed")
+
+	return nil
+}
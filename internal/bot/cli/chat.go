@@ -0,0 +1,297 @@
+// Package cli локальный адаптер чата: сообщения вводятся в терминале и передаются в тот же
+// сервис, что и из Telegram. Нужен для разработки обработчиков без настоящего бота
+package cli
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/service"
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// handleTimeout ограничивает обработку одного сообщения, как UpdateTimeout в Telegram адаптере
+const handleTimeout = 30 * time.Second
+
+const helpText = `Команды чата:
+  #N             нажать кнопку [N] под сообщением или (N) под полем ввода
+  :as ID [имя]   писать от имени другого пользователя
+  :inline текст  результаты inline режима, как при вводе "@бот текст" в другом чате
+  :help          эта справка
+  :quit          выход (или Ctrl+D)
+Любой другой текст отправляется боту как сообщение.`
+
+// User фейковый пользователь Telegram, от имени которого отправляются сообщения
+type User struct {
+	ID        int64
+	FirstName string
+	UserName  string
+}
+
+// Chat чат с ботом в терминале
+type Chat struct {
+	service service.IService
+	user    User
+	in      io.Reader
+	out     io.Writer
+	files   string // каталог для полученных фото и документов
+	logger  *zerolog.Logger
+
+	pushed chan *response.Response // сообщения фоновых задач, выводятся из цикла Run
+	done   chan struct{}           // закрывается при выходе из Run
+
+	lastMessageID int
+	reply         [][]string // текущая клавиатура под полем ввода
+	inline        []inlineMessage
+}
+
+// inlineMessage сообщение бота с кнопками, которые еще можно нажать
+type inlineMessage struct {
+	messageID int
+	rows      [][]inlineButton
+}
+
+type inlineButton struct {
+	text string
+	data string
+	url  string
+}
+
+// New создает чат. Фото и документы от бота сохраняются в каталог files
+func New(service service.IService, user User, in io.Reader, out io.Writer, files string, logger *zerolog.Logger) *Chat {
+	return &Chat{
+		service: service,
+		user:    user,
+		in:      in,
+		out:     out,
+		files:   files,
+		logger:  logger,
+		pushed:  make(chan *response.Response),
+		done:    make(chan struct{}),
+	}
+}
+
+// Send выводит ответ, сформированный не в ответ на сообщение, например напоминание таймера.
+// Ответ выводится из цикла Run, чтобы не смешиваться с обработкой введенной строки.
+// Сообщения в чаты других пользователей не выводятся
+func (c *Chat) Send(ctx context.Context, resp *response.Response) {
+	select {
+	case c.pushed <- resp:
+	case <-c.done:
+	case <-ctx.Done():
+	}
+}
+
+// Run читает строки из in, пока не закончится ввод, не будет введено :quit или не отменен ctx
+func (c *Chat) Run(ctx context.Context) error {
+	defer close(c.done)
+
+	lines := make(chan string)
+	errs := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(c.in)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		errs <- scanner.Err()
+	}()
+
+	fmt.Fprintf(c.out, "Чат с ботом от имени %s (ID %d). %s\n", c.user.FirstName, c.user.ID, "Справка: :help")
+	c.prompt()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			fmt.Fprintln(c.out)
+			return err
+		case line := <-lines:
+			if !c.handleLine(ctx, strings.TrimSpace(line)) {
+				return nil
+			}
+			c.prompt()
+		case resp := <-c.pushed:
+			resp = c.own(resp)
+			if resp.Empty() {
+				continue
+			}
+			fmt.Fprintln(c.out)
+			c.render(resp, 0)
+			c.prompt()
+		}
+	}
+}
+
+// own оставляет в ответе фоновой задачи только сообщения текущему пользователю
+func (c *Chat) own(resp *response.Response) *response.Response {
+	own := response.None()
+	for _, action := range resp.Actions {
+		if text, ok := action.(response.Text); ok && text.ChatID != 0 && text.ChatID != c.user.ID {
+			c.logger.Debug().Int64("chat_id", text.ChatID).Msg("skipped job message to another chat")
+			continue
+		}
+		own.Add(action)
+	}
+	return own
+}
+
+// handleLine выполняет одну введенную строку. Возвращает false, если нужно выйти
+func (c *Chat) handleLine(ctx context.Context, line string) bool {
+	switch {
+	case line == "":
+	case line == ":quit" || line == ":q":
+		return false
+	case line == ":help":
+		fmt.Fprintln(c.out, helpText)
+	case strings.HasPrefix(line, ":as"):
+		c.switchUser(strings.Fields(strings.TrimPrefix(line, ":as")))
+	case strings.HasPrefix(line, ":inline"):
+		c.inlineQuery(ctx, strings.TrimSpace(strings.TrimPrefix(line, ":inline")))
+	case strings.HasPrefix(line, "#"):
+		n, err := strconv.Atoi(strings.TrimPrefix(line, "#"))
+		if err != nil {
+			fmt.Fprintln(c.out, "Укажите номер кнопки, например #1")
+			break
+		}
+		c.press(ctx, n)
+	default:
+		c.send(ctx, line)
+	}
+
+	return true
+}
+
+// switchUser меняет пользователя, от имени которого пишутся сообщения
+func (c *Chat) switchUser(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(c.out, "Использование: :as ID [имя]")
+		return
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		fmt.Fprintln(c.out, "ID пользователя должен быть положительным числом")
+		return
+	}
+
+	c.user = User{ID: id, FirstName: fmt.Sprintf("User %d", id), UserName: fmt.Sprintf("user%d", id)}
+	if len(args) > 1 {
+		c.user.FirstName = strings.Join(args[1:], " ")
+	}
+	c.reply = nil
+	c.inline = nil
+
+	fmt.Fprintf(c.out, "Теперь вы %s (ID %d)\n", c.user.FirstName, c.user.ID)
+}
+
+// send отправляет боту текстовое сообщение
+func (c *Chat) send(ctx context.Context, text string) {
+	msg := c.message(c.nextMessageID(), text)
+	c.handle(ctx, msg, 0)
+}
+
+// press нажимает кнопку с номером n: кнопка клавиатуры отправляет свой текст,
+// inline кнопка — callback с данными, как это делает Telegram
+func (c *Chat) press(ctx context.Context, n int) {
+	i := 0
+	for _, message := range c.inline {
+		for _, row := range message.rows {
+			for _, button := range row {
+				if i++; i != n {
+					continue
+				}
+				if button.url != "" {
+					fmt.Fprintf(c.out, "🔗 %s\n", button.url)
+					return
+				}
+				// Как и Telegram адаптер, передаем данные кнопки текстом сообщения, к которому она прикреплена
+				c.handle(ctx, c.message(message.messageID, button.data), message.messageID)
+				return
+			}
+		}
+	}
+	for _, row := range c.reply {
+		for _, button := range row {
+			if i++; i == n {
+				fmt.Fprintf(c.out, "> %s\n", button)
+				c.send(ctx, button)
+				return
+			}
+		}
+	}
+
+	fmt.Fprintf(c.out, "Нет кнопки с номером %d\n", n)
+}
+
+// inlineQuery выполняет inline запрос и выводит результаты, которые Telegram показал бы над полем ввода
+func (c *Chat) inlineQuery(ctx context.Context, query string) {
+	ctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	answer := c.service.HandleInline(ctx, &tgbotapi.InlineQuery{
+		ID:    strconv.Itoa(c.nextMessageID()),
+		From:  &tgbotapi.User{ID: c.user.ID, FirstName: c.user.FirstName, UserName: c.user.UserName},
+		Query: query,
+	})
+	if answer == nil {
+		return
+	}
+
+	if answer.StartText != "" {
+		fmt.Fprintf(c.out, "[%s] → /start %s\n", answer.StartText, answer.StartParam)
+	}
+	if len(answer.Results) == 0 {
+		fmt.Fprintln(c.out, "Нет результатов")
+		return
+	}
+	for _, result := range answer.Results {
+		fmt.Fprintf(c.out, "• %s — %s\n  %s\n", result.Title, result.Description, plain(result.Text, result.ParseMode))
+		if result.PhotoURL != "" {
+			fmt.Fprintf(c.out, "  🖼 %s\n", result.PhotoURL)
+		}
+	}
+}
+
+// handle передает сообщение сервису и выводит ответ. callbackMessageID — сообщение с нажатой
+// inline кнопкой или 0 для обычного сообщения
+func (c *Chat) handle(ctx context.Context, msg *tgbotapi.Message, callbackMessageID int) {
+	ctx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+
+	resp := c.service.Handle(ctx, msg)
+	c.render(resp, callbackMessageID)
+}
+
+// message создает сообщение от текущего пользователя в личном чате с ботом
+func (c *Chat) message(messageID int, text string) *tgbotapi.Message {
+	return &tgbotapi.Message{
+		MessageID: messageID,
+		From: &tgbotapi.User{
+			ID:        c.user.ID,
+			FirstName: c.user.FirstName,
+			UserName:  c.user.UserName,
+		},
+		Chat: &tgbotapi.Chat{
+			ID:   c.user.ID,
+			Type: "private",
+		},
+		Date: int(time.Now().Unix()),
+		Text: text,
+	}
+}
+
+func (c *Chat) nextMessageID() int {
+	c.lastMessageID++
+	return c.lastMessageID
+}
+
+func (c *Chat) prompt() {
+	fmt.Fprint(c.out, "> ")
+}
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"azhumania/internal/application/response"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// htmlTag тег HTML разметки, который не нужен в терминале
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// render выводит действия ответа в терминал и запоминает кнопки, которые можно нажать
+func (c *Chat) render(resp *response.Response, callbackMessageID int) {
+	if resp.Empty() {
+		return
+	}
+
+	// Кнопки предыдущих сообщений остаются доступными, пока ответ не принес новых
+	var inline []inlineMessage
+
+	for _, action := range resp.Actions {
+		switch a := action.(type) {
+		case response.Text:
+			if a.ChatID != 0 && a.ChatID != c.user.ID {
+				fmt.Fprintf(c.out, "→ [чат %d] %s\n", a.ChatID, plain(a.Text, a.ParseMode))
+				continue
+			}
+			id := c.nextMessageID()
+			fmt.Fprintf(c.out, "🤖 %s\n", plain(a.Text, a.ParseMode))
+			inline = c.applyKeyboard(inline, id, a.Keyboard)
+
+		case response.Photo:
+			id := c.nextMessageID()
+			fmt.Fprintf(c.out, "🖼 %s %s\n", c.save(a.File), plain(a.Caption, a.ParseMode))
+			inline = c.applyKeyboard(inline, id, a.Keyboard)
+
+		case response.Document:
+			id := c.nextMessageID()
+			fmt.Fprintf(c.out, "📎 %s %s\n", c.save(a.File), plain(a.Caption, a.ParseMode))
+			inline = c.applyKeyboard(inline, id, a.Keyboard)
+
+		case response.Edit:
+			id := a.MessageID
+			if id == 0 {
+				id = callbackMessageID
+			}
+			if id == 0 {
+				c.logger.Warn().Msg("skip edit: no message to edit")
+				continue
+			}
+			fmt.Fprintf(c.out, "✏️ (сообщение #%d изменено) %s\n", id, plain(a.Text, a.ParseMode))
+			c.dropInline(id)
+			if a.Keyboard != nil {
+				inline = append(inline, newInlineMessage(id, *a.Keyboard))
+			}
+
+		case response.CallbackAnswer:
+			if callbackMessageID == 0 || a.Text == "" {
+				continue
+			}
+			icon := "💬"
+			if a.ShowAlert {
+				icon = "⚠️"
+			}
+			fmt.Fprintf(c.out, "%s %s\n", icon, a.Text)
+
+		default:
+			c.logger.Error().Str("action", fmt.Sprintf("%T", action)).Msg("unsupported response action")
+		}
+	}
+
+	if len(inline) > 0 {
+		c.inline = inline
+	}
+	c.printChoices()
+}
+
+// applyKeyboard обновляет клавиатуру под полем ввода или добавляет inline кнопки сообщения
+func (c *Chat) applyKeyboard(inline []inlineMessage, messageID int, keyboard response.Keyboard) []inlineMessage {
+	switch k := keyboard.(type) {
+	case response.ReplyKeyboard:
+		c.reply = k.Rows
+	case response.RemoveKeyboard:
+		c.reply = nil
+	case response.InlineKeyboard:
+		inline = append(inline, newInlineMessage(messageID, k))
+	}
+	return inline
+}
+
+// dropInline убирает кнопки измененного сообщения: после правки остаются только новые
+func (c *Chat) dropInline(messageID int) {
+	kept := c.inline[:0]
+	for _, message := range c.inline {
+		if message.messageID != messageID {
+			kept = append(kept, message)
+		}
+	}
+	c.inline = kept
+}
+
+// printChoices выводит доступные кнопки с номерами для нажатия через #N
+func (c *Chat) printChoices() {
+	i := 0
+	var rows []string
+	for _, message := range c.inline {
+		for _, row := range message.rows {
+			var line []string
+			for _, button := range row {
+				i++
+				line = append(line, fmt.Sprintf("[%d] %s", i, button.text))
+			}
+			rows = append(rows, "   "+strings.Join(line, "  "))
+		}
+	}
+	for _, row := range c.reply {
+		var line []string
+		for _, button := range row {
+			i++
+			line = append(line, fmt.Sprintf("(%d) %s", i, button))
+		}
+		rows = append(rows, "   "+strings.Join(line, "  "))
+	}
+
+	if len(rows) > 0 {
+		fmt.Fprintln(c.out, strings.Join(rows, "\n"))
+	}
+}
+
+// save сохраняет файл из ответа в каталог files и возвращает путь к нему
+func (c *Chat) save(file response.File) string {
+	path := filepath.Join(c.files, filepath.Base(file.Name))
+	if err := os.WriteFile(path, file.Data, 0o644); err != nil {
+		c.logger.Error().Err(err).Str("path", path).Msg("failed to save file")
+		return file.Name
+	}
+	return path
+}
+
+func newInlineMessage(messageID int, keyboard response.InlineKeyboard) inlineMessage {
+	message := inlineMessage{messageID: messageID}
+	for _, row := range keyboard.Rows {
+		var buttons []inlineButton
+		for _, b := range row {
+			buttons = append(buttons, inlineButton{text: b.Text, data: b.Data, url: b.URL})
+		}
+		message.rows = append(message.rows, buttons)
+	}
+	return message
+}
+
+// plain убирает разметку, которую Telegram отобразил бы форматированием
+func plain(text string, mode response.ParseMode) string {
+	switch mode {
+	case response.ParseModeHTML:
+		return html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+	case response.ParseModeMarkdownV2:
+		return strings.NewReplacer(`\`, "", "*", "", "__", "", "`", "").Replace(text)
+	default:
+		return text
+	}
+}
@@ -0,0 +1,31 @@
+package telegram
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var _ Client = &tgbotapi.BotAPI{}
+
+// Sender отправляет запросы в Telegram Bot API: сообщения, правки сообщений, ответы на callback
+type Sender interface {
+	// Send отправляет сообщение или правку сообщения и возвращает результат
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+
+	// Request выполняет метод API, результат которого не является сообщением
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+
+	// MakeRequest выполняет произвольный метод API с готовыми параметрами
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+// UpdateSource поставляет обновления через long polling
+type UpdateSource interface {
+	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	StopReceivingUpdates()
+}
+
+// Client часть Telegram Bot API, которую использует бот
+type Client interface {
+	Sender
+	UpdateSource
+}
@@ -0,0 +1,493 @@
+package telegram
+
+import (
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/config"
+	"azhumania/internal/metrics"
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// errDispatcherClosed сообщение не поставлено в очередь: бот останавливается
+var errDispatcherClosed = errors.New("dispatcher closed")
+
+// globalLimitKey ключ общего лимита отправки в limiter
+const globalLimitKey = "global"
+
+// outgoing исходящее сообщение в очереди отправителя
+type outgoing struct {
+	chatID    int64
+	chattable tgbotapi.Chattable
+	action    string          // имя действия для метрик
+	logger    *zerolog.Logger // логгер обновления или задачи, которые сформировали сообщение
+}
+
+// sendErrorKind вид ошибки Telegram Bot API
+type sendErrorKind int
+
+const (
+	sendErrorTransient   sendErrorKind = iota // сеть или ошибка на стороне Telegram: можно повторить
+	sendErrorRateLimited                      // 429: повторить после retry_after
+	sendErrorUnreachable                      // бот заблокирован или чат не найден: писать в чат бесполезно
+	sendErrorPermanent                        // некорректный запрос: повтор не поможет
+)
+
+// dispatcher отправляет исходящие сообщения с ограничением частоты: общим для бота и отдельным
+// для каждого чата. Сообщения одного чата попадают в очередь одного отправителя и уходят по порядку.
+// После 429 все отправители ждут retry_after, сетевые ошибки повторяются с растущей паузой.
+// Если Telegram отвечает, что бот заблокирован или чат не найден, в фоне вызывается unreachable
+type dispatcher struct {
+	client      Sender
+	cfg         config.Send
+	limiter     ratelimit.Limiter
+	global      ratelimit.Limit
+	perChat     ratelimit.Limit
+	unreachable func(chatID int64)
+	logger      *zerolog.Logger
+
+	queues      []chan outgoing
+	wg          sync.WaitGroup // отправители
+	feeders     sync.WaitGroup // фоновые постановки в очередь сообщений в другие чаты
+	enqueuing   sync.WaitGroup // вызовы enqueue, которые ждут места в очереди
+	callbacks   sync.WaitGroup // фоновые вызовы unreachable
+	marking     sync.Map       // чаты, для которых сейчас выполняется unreachable
+	pausedUntil atomic.Int64   // до какого момента (UnixNano) Telegram попросил не отправлять
+
+	mu      sync.RWMutex
+	closing bool          // новые фоновые постановки не запускаются
+	closed  bool          // новые сообщения не принимаются, очереди закрываются
+	stop    chan struct{} // закрывается, когда ждать места в очереди больше нельзя
+}
+
+// newDispatcher создает и запускает отправителей исходящих сообщений
+func newDispatcher(client Sender, cfg config.Send, unreachable func(chatID int64), logger *zerolog.Logger) *dispatcher {
+	d := &dispatcher{
+		client:      client,
+		cfg:         cfg,
+		limiter:     ratelimit.NewMemoryLimiter(),
+		global:      ratelimit.PerSecond(cfg.PerSecond, 1),
+		perChat:     ratelimit.PerMinute(cfg.ChatPerMinute, cfg.ChatBurst),
+		unreachable: unreachable,
+		logger:      logger,
+		queues:      make([]chan outgoing, cfg.Workers),
+		stop:        make(chan struct{}),
+	}
+
+	for i := range d.queues {
+		d.queues[i] = make(chan outgoing, cfg.QueueSize)
+		d.wg.Add(1)
+		go d.work(d.queues[i])
+	}
+
+	return d
+}
+
+// enqueue ставит сообщение в очередь отправителя его чата. Если очередь заполнена, вызов
+// блокируется, пока не освободится место, не будет отменен ctx или не начнется остановка
+func (d *dispatcher) enqueue(ctx context.Context, msg outgoing) error {
+	// Блокировка не держится во время ожидания места, иначе close не смог бы начать остановку.
+	// Очереди закрываются только после того, как все начатые вызовы вернулись
+	d.mu.RLock()
+	if d.closed {
+		d.mu.RUnlock()
+		return errDispatcherClosed
+	}
+	d.enqueuing.Add(1)
+	d.mu.RUnlock()
+	defer d.enqueuing.Done()
+
+	queue := d.queues[uint64(msg.chatID)%uint64(len(d.queues))]
+	select {
+	case queue <- msg:
+		metrics.SendQueueDepth.Inc()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-d.stop:
+		return errDispatcherClosed
+	}
+}
+
+// enqueueAll ставит сообщения в очередь в фоне: рассылка больше очереди не держит обработчик
+// обновления, пока отправители освобождают место. Во время остановки сообщения ставятся в очередь сразу
+func (d *dispatcher) enqueueAll(msgs []outgoing) {
+	d.mu.RLock()
+	closing := d.closing
+	if !closing {
+		d.feeders.Add(1)
+	}
+	d.mu.RUnlock()
+
+	if closing {
+		d.feed(msgs)
+		return
+	}
+	go func() {
+		defer d.feeders.Done()
+		d.feed(msgs)
+	}()
+}
+
+// feed ставит сообщения в очередь по одному. Если очередь закрылась, оставшиеся сообщения
+// учитываются как ошибки отправки
+func (d *dispatcher) feed(msgs []outgoing) {
+	for i, msg := range msgs {
+		if err := d.enqueue(context.Background(), msg); err != nil {
+			dropped := len(msgs) - i
+			metrics.TelegramSendErrors.WithLabelValues(msg.action).Add(float64(dropped))
+			msg.logger.Error().Err(err).Int("dropped", dropped).Msg("failed to queue messages")
+			return
+		}
+	}
+}
+
+// work отправляет сообщения из очереди одного отправителя до ее закрытия
+func (d *dispatcher) work(queue <-chan outgoing) {
+	defer d.wg.Done()
+
+	w := &worker{dispatcher: d, queue: queue, chats: make(map[int64]*chatQueue)}
+	w.run()
+}
+
+// chatQueue отложенные сообщения одного чата
+type chatQueue struct {
+	msgs    []outgoing
+	readyAt time.Time // раньше этого момента лимит чата не даст отправить сообщение
+}
+
+// worker отправитель одной очереди. Сообщения чата, который исчерпал свой лимит, откладываются,
+// и отправитель тем временем обслуживает другие чаты своей очереди. Порядок сообщений внутри
+// чата сохраняется
+type worker struct {
+	*dispatcher
+	queue <-chan outgoing // nil — очередь закрыта
+	chats map[int64]*chatQueue
+	order []int64 // чаты с отложенными сообщениями, по очереди обслуживания
+	held  int     // отложенных сообщений
+}
+
+// run отправляет сообщения, пока очередь не закрыта или остались отложенные сообщения
+func (w *worker) run() {
+	for {
+		w.receive()
+		if msg, ok := w.next(time.Now()); ok {
+			metrics.SendQueueDepth.Dec()
+			w.deliver(msg)
+			continue
+		}
+		if w.queue == nil && w.held == 0 {
+			return
+		}
+		w.wait()
+	}
+}
+
+// receive забирает из очереди уже пришедшие сообщения, не блокируясь. Отложенных сообщений
+// не больше длины очереди: дальше постановка в очередь ждет, как и без откладывания
+func (w *worker) receive() {
+	for w.queue != nil && !w.full() {
+		select {
+		case msg, ok := <-w.queue:
+			if !ok {
+				w.queue = nil
+				return
+			}
+			w.hold(msg)
+		default:
+			return
+		}
+	}
+}
+
+// wait ждет нового сообщения или момента, когда лимит отпустит один из отложенных чатов
+func (w *worker) wait() {
+	var ready <-chan time.Time
+	if w.held > 0 {
+		earliest := w.chats[w.order[0]].readyAt
+		for _, chatID := range w.order[1:] {
+			if readyAt := w.chats[chatID].readyAt; readyAt.Before(earliest) {
+				earliest = readyAt
+			}
+		}
+		timer := time.NewTimer(time.Until(earliest))
+		defer timer.Stop()
+		ready = timer.C
+	}
+
+	queue := w.queue
+	if w.full() {
+		queue = nil
+	}
+
+	select {
+	case msg, ok := <-queue:
+		if !ok {
+			w.queue = nil
+			return
+		}
+		w.hold(msg)
+	case <-ready:
+	}
+}
+
+// full сообщает, что отложено столько сообщений, сколько вмещает очередь
+func (w *worker) full() bool {
+	return w.held >= max(cap(w.queue), 1)
+}
+
+// hold откладывает сообщение в конец очереди его чата
+func (w *worker) hold(msg outgoing) {
+	chat, ok := w.chats[msg.chatID]
+	if !ok {
+		chat = &chatQueue{}
+		w.chats[msg.chatID] = chat
+		w.order = append(w.order, msg.chatID)
+	}
+	chat.msgs = append(chat.msgs, msg)
+	w.held++
+}
+
+// next возвращает первое сообщение чата, лимит которого позволяет отправку. Чаты проверяются
+// по очереди, а обслуженный чат переходит в конец, чтобы активный чат не задерживал остальные
+func (w *worker) next(now time.Time) (outgoing, bool) {
+	for i, chatID := range w.order {
+		chat := w.chats[chatID]
+		if now.Before(chat.readyAt) {
+			continue
+		}
+		// Ошибок у лимитера в памяти не бывает
+		if ok, _ := w.limiter.Allow(context.Background(), strconv.FormatInt(chatID, 10), w.perChat); !ok {
+			// За perChat.Every в корзине чата появляется новый токен
+			chat.readyAt = now.Add(w.perChat.Every)
+			continue
+		}
+
+		msg := chat.msgs[0]
+		chat.msgs = chat.msgs[1:]
+		w.held--
+		w.order = slices.Delete(w.order, i, i+1)
+		if len(chat.msgs) == 0 {
+			delete(w.chats, chatID)
+		} else {
+			w.order = append(w.order, chatID)
+		}
+		return msg, true
+	}
+	return outgoing{}, false
+}
+
+// deliver отправляет сообщение с соблюдением общего лимита и повторяет отправку после временных
+// ошибок. Лимит чата проверяет worker до вызова. Повторы после 429 не считаются в MaxRetries:
+// Telegram сам говорит, сколько ждать
+func (d *dispatcher) deliver(msg outgoing) {
+	retries := 0
+	for attempt := 0; ; attempt++ {
+		d.waitPause()
+		// Ошибок у лимитера в памяти не бывает, а контекст не отменяется
+		_ = ratelimit.Wait(context.Background(), d.limiter, globalLimitKey, d.global)
+
+		err := d.send(msg.chattable)
+		if err == nil {
+			return
+		}
+
+		kind, retryAfter := classifySendError(err)
+		switch {
+		case kind == sendErrorUnreachable:
+			metrics.TelegramSendErrors.WithLabelValues(msg.action).Inc()
+			msg.logger.Warn().Err(err).Int64("chat_id", msg.chatID).Msg("chat unreachable, message dropped")
+			d.markUnreachable(msg.chatID)
+			return
+		case kind == sendErrorPermanent || kind == sendErrorTransient && retries >= d.cfg.MaxRetries:
+			metrics.TelegramSendErrors.WithLabelValues(msg.action).Inc()
+			msg.logger.Error().Err(err).Str("action", msg.action).Int("attempts", attempt+1).Msg("failed to send message")
+			return
+		}
+
+		wait := d.backoff(retries)
+		reason := "transient"
+		if kind == sendErrorRateLimited {
+			reason = "rate_limited"
+			if retryAfter > 0 {
+				wait = retryAfter
+			}
+			// Лимит Telegram общий для бота: ждут все отправители
+			d.pause(wait)
+		} else {
+			retries++
+		}
+		metrics.TelegramSendRetries.WithLabelValues(reason).Inc()
+		msg.logger.Warn().Err(err).Str("action", msg.action).Dur("retry_in", wait).Msg("retrying message")
+
+		if kind != sendErrorRateLimited {
+			time.Sleep(wait)
+		}
+	}
+}
+
+// markUnreachable вызывает unreachable в фоне, чтобы запись в базу не задерживала отправителя.
+// Пока вызов для чата не закончился, следующие сообщения в тот же чат его не повторяют
+func (d *dispatcher) markUnreachable(chatID int64) {
+	if d.unreachable == nil {
+		return
+	}
+	if _, marking := d.marking.LoadOrStore(chatID, struct{}{}); marking {
+		return
+	}
+
+	d.callbacks.Add(1)
+	go func() {
+		defer d.callbacks.Done()
+		defer d.marking.Delete(chatID)
+		d.unreachable(chatID)
+	}()
+}
+
+// call выполняет метод API, результат которого нужен сразу (ответ на callback или inline запрос):
+// без очереди и лимитов отправки, но с повторами, пока не отменен ctx. Как и в deliver,
+// повторы после 429 не считаются в MaxRetries
+func (d *dispatcher) call(ctx context.Context, c tgbotapi.Chattable) error {
+	retries := 0
+	for {
+		_, err := d.client.Request(c)
+		if err == nil {
+			return nil
+		}
+
+		kind, retryAfter := classifySendError(err)
+		if kind == sendErrorPermanent || kind == sendErrorUnreachable || kind == sendErrorTransient && retries >= d.cfg.MaxRetries {
+			return err
+		}
+
+		wait, reason := d.backoff(retries), "transient"
+		if kind == sendErrorRateLimited {
+			reason = "rate_limited"
+			if retryAfter > 0 {
+				wait = retryAfter
+			}
+		} else {
+			retries++
+		}
+		metrics.TelegramSendRetries.WithLabelValues(reason).Inc()
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// send отправляет сообщение. Правки отправляются через Request: для inline сообщений
+// Telegram возвращает true вместо сообщения, и Send не смог бы разобрать ответ
+func (d *dispatcher) send(c tgbotapi.Chattable) error {
+	if _, ok := c.(tgbotapi.EditMessageTextConfig); ok {
+		_, err := d.client.Request(c)
+		return err
+	}
+	_, err := d.client.Send(c)
+	return err
+}
+
+// backoff возвращает паузу перед повтором номер attempt, считая с 0
+func (d *dispatcher) backoff(attempt int) time.Duration {
+	return d.cfg.RetryBackoff << attempt
+}
+
+// pause откладывает отправку всех сообщений на wait
+func (d *dispatcher) pause(wait time.Duration) {
+	until := time.Now().Add(wait).UnixNano()
+	for {
+		current := d.pausedUntil.Load()
+		if until <= current || d.pausedUntil.CompareAndSwap(current, until) {
+			return
+		}
+	}
+}
+
+// waitPause ждет окончания паузы после 429
+func (d *dispatcher) waitPause() {
+	if wait := time.Until(time.Unix(0, d.pausedUntil.Load())); wait > 0 {
+		time.Sleep(wait)
+	}
+}
+
+// close дожидается отправки сообщений из очередей, пока не истечет ctx
+func (d *dispatcher) close(ctx context.Context) error {
+	d.mu.Lock()
+	d.closing = true
+	d.mu.Unlock()
+
+	// Фоновые постановки дописывают рассылки, пока отправители освобождают место
+	err := waitGroup(ctx, &d.feeders)
+	close(d.stop)
+	d.feeders.Wait()
+
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+	// После закрытия stop ожидающие вызовы enqueue возвращаются сразу
+	d.enqueuing.Wait()
+	for _, queue := range d.queues {
+		close(queue)
+	}
+
+	if err != nil {
+		return err
+	}
+	if err := waitGroup(ctx, &d.wg); err != nil {
+		return err
+	}
+	// Отправители остановлены, новых вызовов unreachable не будет
+	return waitGroup(ctx, &d.callbacks)
+}
+
+// waitGroup ждет wg, пока не истечет ctx
+func waitGroup(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// classifySendError определяет вид ошибки Telegram Bot API. При загрузке файлов библиотека
+// не заполняет код ошибки, поэтому вид определяется и по началу описания
+func classifySendError(err error) (sendErrorKind, time.Duration) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return sendErrorTransient, 0
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case apiErr.Code == http.StatusTooManyRequests || apiErr.RetryAfter > 0 || strings.HasPrefix(message, "too many requests"):
+		return sendErrorRateLimited, time.Duration(apiErr.RetryAfter) * time.Second
+	case apiErr.Code == http.StatusForbidden || strings.HasPrefix(message, "forbidden"):
+		// Бот заблокирован пользователем, аккаунт удален или бота исключили из группы
+		return sendErrorUnreachable, 0
+	case strings.Contains(message, "chat not found") || strings.Contains(message, "user not found"):
+		return sendErrorUnreachable, 0
+	case apiErr.Code >= http.StatusInternalServerError:
+		return sendErrorTransient, 0
+	default:
+		return sendErrorPermanent, 0
+	}
+}
@@ -0,0 +1,56 @@
+package telegram
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// handleInlineQuery отвечает на inline запрос (@бот в поле ввода любого чата)
+func (t *TelegramBot) handleInlineQuery(ctx context.Context, query *tgbotapi.InlineQuery) {
+	answer := t.service.HandleInline(ctx, query)
+	if answer == nil {
+		return
+	}
+
+	// Пустой список результатов тоже отправляем, иначе Telegram показывает загрузку до таймаута
+	config := tgbotapi.InlineConfig{
+		InlineQueryID:     query.ID,
+		Results:           make([]interface{}, 0, len(answer.Results)),
+		CacheTime:         int(answer.CacheTime.Seconds()),
+		IsPersonal:        answer.Personal,
+		SwitchPMText:      answer.StartText,
+		SwitchPMParameter: answer.StartParam,
+	}
+	for _, result := range answer.Results {
+		config.Results = append(config.Results, inlineResult(result))
+	}
+
+	if err := t.sender.call(ctx, config); err != nil {
+		metrics.TelegramSendErrors.WithLabelValues("inline_answer").Inc()
+		logging.FromContext(ctx, t.logger).Error().Err(err).Msg("failed to answer inline query")
+	}
+}
+
+// inlineResult преобразует результат inline запроса в статью или фото по ссылке
+func inlineResult(result response.InlineResult) interface{} {
+	if result.PhotoURL != "" {
+		photo := tgbotapi.NewInlineQueryResultPhotoWithThumb(result.ID, result.PhotoURL, result.PhotoURL)
+		photo.Title = result.Title
+		photo.Description = result.Description
+		photo.Caption = result.Text
+		photo.ParseMode = string(result.ParseMode)
+		return photo
+	}
+
+	article := tgbotapi.NewInlineQueryResultArticle(result.ID, result.Title, result.Text)
+	article.Description = result.Description
+	article.InputMessageContent = tgbotapi.InputTextMessageContent{
+		Text:      result.Text,
+		ParseMode: string(result.ParseMode),
+	}
+	return article
+}
@@ -1,59 +1,63 @@
 package telegram
 
 import (
+	"azhumania/internal/application/response"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-// GetMainKeyboard возвращает основную клавиатуру с командами
-func GetMainKeyboard() tgbotapi.ReplyKeyboardMarkup {
-	keyboard := tgbotapi.ReplyKeyboardMarkup{
-		Keyboard: [][]tgbotapi.KeyboardButton{
-			{
-				tgbotapi.KeyboardButton{Text: "📊 Статистика"},
-				tgbotapi.KeyboardButton{Text: "❓ Помощь"},
-			},
-			{
-				tgbotapi.KeyboardButton{Text: "🏠 Главное меню"},
-			},
-		},
-		ResizeKeyboard:  true,
-		OneTimeKeyboard: false,
-		Selective:       false,
+// replyMarkup преобразует клавиатуру ответа в разметку Telegram
+func replyMarkup(keyboard response.Keyboard) interface{} {
+	switch k := keyboard.(type) {
+	case response.ReplyKeyboard:
+		return replyKeyboard(k)
+	case response.InlineKeyboard:
+		return inlineKeyboard(k)
+	case *response.InlineKeyboard:
+		if k == nil {
+			return nil
+		}
+		return inlineKeyboard(*k)
+	case response.RemoveKeyboard:
+		return tgbotapi.NewRemoveKeyboard(false)
+	default:
+		return nil
 	}
-
-	return keyboard
 }
 
-// GetInlineKeyboard возвращает inline клавиатуру для быстрых действий
-func GetInlineKeyboard() tgbotapi.InlineKeyboardMarkup {
-	keyboard := tgbotapi.InlineKeyboardMarkup{
-		InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{
-			{
-				tgbotapi.InlineKeyboardButton{
-					Text:         "📊 Статистика",
-					CallbackData: &[]string{"stats"}[0],
-				},
-				tgbotapi.InlineKeyboardButton{
-					Text:         "❓ Помощь",
-					CallbackData: &[]string{"help"}[0],
-				},
-			},
-			{
-				tgbotapi.InlineKeyboardButton{
-					Text:         "🏠 Главное меню",
-					CallbackData: &[]string{"start"}[0],
-				},
-			},
-		},
+// replyKeyboard возвращает клавиатуру под полем ввода
+func replyKeyboard(k response.ReplyKeyboard) tgbotapi.ReplyKeyboardMarkup {
+	rows := make([][]tgbotapi.KeyboardButton, 0, len(k.Rows))
+	for _, row := range k.Rows {
+		buttons := make([]tgbotapi.KeyboardButton, 0, len(row))
+		for _, text := range row {
+			buttons = append(buttons, tgbotapi.KeyboardButton{Text: text})
+		}
+		rows = append(rows, buttons)
 	}
 
-	return keyboard
+	return tgbotapi.ReplyKeyboardMarkup{
+		Keyboard:        rows,
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: false,
+		Selective:       false,
+	}
 }
 
-// RemoveKeyboard возвращает команду для скрытия клавиатуры
-func RemoveKeyboard() tgbotapi.ReplyKeyboardRemove {
-	return tgbotapi.ReplyKeyboardRemove{
-		RemoveKeyboard: true,
-		Selective:      false,
+// inlineKeyboard возвращает клавиатуру под сообщением
+func inlineKeyboard(k response.InlineKeyboard) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(k.Rows))
+	for _, row := range k.Rows {
+		buttons := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, b := range row {
+			if b.URL != "" {
+				buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonURL(b.Text, b.URL))
+				continue
+			}
+			buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData(b.Text, b.Data))
+		}
+		rows = append(rows, buttons)
 	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
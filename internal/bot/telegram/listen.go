@@ -1,77 +1,226 @@
 package telegram
 
 import (
+	"azhumania/internal/config"
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"context"
 	"fmt"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
 )
 
-func (t *TelegramBot) Listen() {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+// statsInterval период записи метрик пула обработчиков в лог
+const statsInterval = time.Minute
+
+// Listen получает обновления и распределяет их по обработчикам, пока не будет отменен ctx.
+// После возврата нужно вызвать Shutdown, чтобы обработать уже полученные обновления
+func (t *TelegramBot) Listen(ctx context.Context) error {
+	updates, err := t.receiveUpdates()
+	if err != nil {
+		return err
+	}
+	t.updates = updates
+	t.registerCommands()
+	t.pool = newWorkerPool(t.cfg.Workers, t.cfg.QueueSize, t.handleUpdate)
+
+	go t.pool.logStats(ctx, statsInterval, t.logger)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return t.receiveErr()
+			}
+			metrics.UpdatesReceived.WithLabelValues(updateType(update)).Inc()
 
-	updates := t.bot.GetUpdatesChan(u)
+			// Постановка в очередь не прерывается сигналом остановки, чтобы не потерять
+			// уже полученное обновление: воркеры освобождают место не позже UpdateTimeout
+			_ = t.pool.dispatch(context.WithoutCancel(ctx), update)
+		}
+	}
+}
 
-	for update := range updates {
-		// Обрабатываем сообщения
-		if update.Message != nil {
-			answer, keyboard := t.service.Handle(update.Message)
+// Stats возвращает метрики очередей и времени обработки обновлений
+func (t *TelegramBot) Stats() PoolStats {
+	if t.pool == nil {
+		return PoolStats{}
+	}
+	return t.pool.stats()
+}
 
-			msg := tgbotapi.NewMessage(update.Message.Chat.ID, answer)
+// Shutdown прекращает прием обновлений и обрабатывает уже полученные, пока не истечет ctx
+func (t *TelegramBot) Shutdown(ctx context.Context) error {
+	if t.updates == nil {
+		return nil
+	}
 
-			// Добавляем клавиатуру, если она есть
-			if keyboard != nil {
-				switch k := keyboard.(type) {
-				case tgbotapi.ReplyKeyboardMarkup:
-					msg.ReplyMarkup = k
-				case tgbotapi.ReplyKeyboardRemove:
-					msg.ReplyMarkup = k
+	if err := t.drain(ctx); err != nil {
+		return err
+	}
+
+	return t.pool.close(ctx)
+}
+
+// Close дожидается отправки исходящих сообщений, пока не истечет ctx. Вызывается после
+// Shutdown и остановки фоновых задач, чтобы их сообщения тоже ушли
+func (t *TelegramBot) Close(ctx context.Context) error {
+	return t.sender.close(ctx)
+}
+
+// drain останавливает источник обновлений и передает в пул обновления, полученные до остановки
+func (t *TelegramBot) drain(ctx context.Context) error {
+	if t.server == nil {
+		// Long polling: обрабатываем то, что уже лежит в буфере канала
+		t.bot.StopReceivingUpdates()
+		for {
+			select {
+			case update, ok := <-t.updates:
+				if !ok {
+					return nil
+				}
+				metrics.UpdatesReceived.WithLabelValues(updateType(update)).Inc()
+				if err := t.pool.dispatch(ctx, update); err != nil {
+					return err
 				}
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+				return nil
 			}
+		}
+	}
+
+	// Вебхук: останавливаем сервер и параллельно дочитываем канал, чтобы запросы,
+	// ожидающие места в канале, могли завершиться. Канал закрывается после остановки сервера
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- t.stopWebhook(ctx)
+	}()
 
-			if _, err := t.bot.Send(msg); err != nil {
-				fmt.Println("error Send", err)
+	for {
+		select {
+		case update, ok := <-t.updates:
+			if !ok {
+				return <-stopped
+			}
+			metrics.UpdatesReceived.WithLabelValues(updateType(update)).Inc()
+			if err := t.pool.dispatch(ctx, update); err != nil {
+				return err
 			}
+		case <-ctx.Done():
+			return ctx.Err()
 		}
+	}
+}
 
-		// Обрабатываем callback-запросы от inline кнопок
-		if update.CallbackQuery != nil {
-			t.handleCallbackQuery(update.CallbackQuery)
-		}
+// registerCommands публикует меню команд бота. Ошибка не мешает работе бота
+func (t *TelegramBot) registerCommands() {
+	commands := t.service.Commands()
+	if len(commands) == 0 {
+		return
+	}
+
+	if _, err := t.bot.Request(tgbotapi.NewSetMyCommands(commands...)); err != nil {
+		metrics.TelegramSendErrors.WithLabelValues("set_commands").Inc()
+		t.logger.Error().Err(err).Msg("failed to set bot commands")
+	}
+}
+
+// receiveUpdates возвращает канал обновлений в зависимости от выбранного режима
+func (t *TelegramBot) receiveUpdates() (tgbotapi.UpdatesChannel, error) {
+	if t.cfg.Mode == config.ModeWebhook {
+		return t.startWebhook()
+	}
+
+	// Telegram не отдает обновления через getUpdates, пока зарегистрирован вебхук
+	if _, err := t.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return nil, fmt.Errorf("delete webhook: %w", err)
+	}
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+
+	return t.bot.GetUpdatesChan(u), nil
+}
+
+// handleUpdate обрабатывает одно обновление независимо от способа его получения
+// Обработка не прерывается отменой ctx (сигналом остановки), но ограничена UpdateTimeout
+func (t *TelegramBot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), t.cfg.UpdateTimeout)
+	defer cancel()
+
+	logger := t.updateLogger(update)
+	ctx = logging.WithLogger(ctx, logger)
+	logger.Debug().Str("type", updateType(update)).Msg("update received")
+
+	// Обрабатываем сообщения
+	if update.Message != nil {
+		resp := t.service.Handle(ctx, update.Message)
+		t.render(ctx, update.Message.Chat.ID, nil, resp)
+	}
+
+	// Обрабатываем callback-запросы от inline кнопок
+	if update.CallbackQuery != nil {
+		t.handleCallbackQuery(ctx, update.CallbackQuery)
+	}
+
+	// Обрабатываем inline запросы
+	if update.InlineQuery != nil {
+		t.handleInlineQuery(ctx, update.InlineQuery)
 	}
 }
 
 // handleCallbackQuery обрабатывает callback-запросы от inline кнопок
-func (t *TelegramBot) handleCallbackQuery(callback *tgbotapi.CallbackQuery) {
-	// Отвечаем на callback, чтобы убрать "часики" у кнопки
-	callbackResponse := tgbotapi.NewCallback(callback.ID, "")
-	if _, err := t.bot.Request(callbackResponse); err != nil {
-		fmt.Println("error callback response", err)
+func (t *TelegramBot) handleCallbackQuery(ctx context.Context, callback *tgbotapi.CallbackQuery) {
+	if callback.Message == nil {
+		// Кнопка под inline сообщением: чата нет, можно только ответить на callback
+		t.render(ctx, 0, callback, nil)
+		return
 	}
 
 	// Создаем фейковое сообщение для обработки
 	fakeMessage := &tgbotapi.Message{
-		From: callback.From,
-		Chat: callback.Message.Chat,
-		Text: callback.Data,
+		MessageID: callback.Message.MessageID,
+		From:      callback.From,
+		Chat:      callback.Message.Chat,
+		Text:      callback.Data,
 	}
 
-	answer, keyboard := t.service.Handle(fakeMessage)
-
-	// Отправляем ответ в чат
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, answer)
+	resp := t.service.Handle(ctx, fakeMessage)
+	t.render(ctx, callback.Message.Chat.ID, callback, resp)
+}
 
-	// Добавляем клавиатуру, если она есть
-	if keyboard != nil {
-		switch k := keyboard.(type) {
-		case tgbotapi.ReplyKeyboardMarkup:
-			msg.ReplyMarkup = k
-		case tgbotapi.ReplyKeyboardRemove:
-			msg.ReplyMarkup = k
-		}
+// updateLogger возвращает логгер с идентификаторами обновления, пользователя и чата
+func (t *TelegramBot) updateLogger(update tgbotapi.Update) *zerolog.Logger {
+	fields := t.logger.With().Int("update_id", update.UpdateID)
+	if user := update.SentFrom(); user != nil {
+		fields = fields.Int64("user_id", user.ID)
 	}
+	if chat := update.FromChat(); chat != nil {
+		fields = fields.Int64("chat_id", chat.ID)
+	}
+
+	logger := fields.Logger()
+	return &logger
+}
 
-	if _, err := t.bot.Send(msg); err != nil {
-		fmt.Println("error Send callback response", err)
+// updateType возвращает тип обновления для метрик
+func updateType(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil:
+		return "message"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	case update.InlineQuery != nil:
+		return "inline_query"
+	case update.EditedMessage != nil:
+		return "edited_message"
+	default:
+		return "other"
 	}
 }
@@ -0,0 +1,276 @@
+package telegram_test
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/scheduler"
+	"azhumania/internal/bot/telegram"
+	"azhumania/internal/bot/telegram/telegramtest"
+	"azhumania/internal/config"
+	"azhumania/internal/service"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// echoService отвечает на сообщение его же текстом и запоминает обработанные сообщения
+type echoService struct {
+	mu      sync.Mutex
+	handled []string
+	delay   time.Duration
+}
+
+func (s *echoService) Handle(ctx context.Context, msg *tgbotapi.Message) *response.Response {
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	s.handled = append(s.handled, msg.Text)
+	s.mu.Unlock()
+
+	if msg.Text == "/edit" {
+		return response.New(response.Edit{Text: "edited"}, response.CallbackAnswer{Text: "ok"})
+	}
+	return response.Message(msg.Text)
+}
+
+func (s *echoService) HandleInline(ctx context.Context, query *tgbotapi.InlineQuery) *response.InlineAnswer {
+	return &response.InlineAnswer{Results: []response.InlineResult{{ID: "echo", Title: query.Query, Text: query.Query}}}
+}
+
+func (s *echoService) Commands() []tgbotapi.BotCommand                 { return nil }
+func (s *echoService) APIHandler() http.Handler                        { return http.NotFoundHandler() }
+func (s *echoService) ChartsHandler() http.Handler                     { return http.NotFoundHandler() }
+func (s *echoService) HealthChecks() []service.HealthCheck             { return nil }
+func (s *echoService) RunJobs(ctx context.Context, _ scheduler.Sender) { <-ctx.Done() }
+func (s *echoService) MarkUnreachable(context.Context, int64)          {}
+func (s *echoService) Close(context.Context) error                     { return nil }
+
+// newTestBot создает бота в режиме long polling поверх фейкового клиента
+func newTestBot(t *testing.T, service *echoService) (*telegram.TelegramBot, *telegramtest.Client) {
+	t.Helper()
+	return newTestBotWith(t, service, nil)
+}
+
+// newTestBotWith создает бота, как newTestBot, и дает изменить настройки перед созданием
+func newTestBotWith(t *testing.T, service *echoService, configure func(cfg *config.Telegram)) (*telegram.TelegramBot, *telegramtest.Client) {
+	t.Helper()
+
+	cfg := config.Default().Telegram
+	cfg.Workers = 4
+	// Лимиты Telegram в тестах не нужны: сообщения в один чат идут подряд
+	cfg.Send.ChatPerMinute = 60000
+	cfg.Send.ChatBurst = 1000
+	cfg.Send.PerSecond = 10000
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	logger := zerolog.New(zerolog.NewTestWriter(t))
+	client := telegramtest.NewClient(256)
+	return telegram.NewWithClient(cfg, client, service, &logger), client
+}
+
+// run запускает Listen и возвращает функцию, которая останавливает бота так же, как main:
+// Shutdown дообрабатывает полученные обновления, Close дожидается отправки очереди
+func run(t *testing.T, bot *telegram.TelegramBot) (stop func()) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- bot.Listen(ctx)
+	}()
+
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("Listen: %v", err)
+		}
+
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		if err := bot.Shutdown(shutdownCtx); err != nil {
+			t.Fatalf("Shutdown: %v", err)
+		}
+		if err := bot.Close(shutdownCtx); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+}
+
+// textsByChat группирует тексты отправленных сообщений по чатам в порядке отправки
+func textsByChat(messages []tgbotapi.MessageConfig) map[int64][]string {
+	texts := make(map[int64][]string)
+	for _, msg := range messages {
+		texts[msg.ChatID] = append(texts[msg.ChatID], msg.Text)
+	}
+	return texts
+}
+
+func TestListenRepliesInOrderWithinChat(t *testing.T) {
+	bot, client := newTestBot(t, &echoService{delay: time.Millisecond})
+
+	const perUser = 20
+	users := []int64{101, 102, 103}
+	want := make(map[int64][]string)
+	for i := 0; i < perUser; i++ {
+		for _, userID := range users {
+			text := fmt.Sprintf("%d-%d", userID, i)
+			client.Push(telegramtest.MessageUpdate(userID, text))
+			want[userID] = append(want[userID], text)
+		}
+	}
+
+	stop := run(t, bot)
+	if !client.WaitSent(perUser*len(users), 5*time.Second) {
+		t.Fatalf("sent %d messages, want %d", len(client.Sent()), perUser*len(users))
+	}
+	stop()
+
+	got := textsByChat(client.SentMessages())
+	for _, userID := range users {
+		if fmt.Sprint(got[userID]) != fmt.Sprint(want[userID]) {
+			t.Errorf("chat %d replies = %v, want %v", userID, got[userID], want[userID])
+		}
+	}
+}
+
+func TestListenCallbackEditsMessageAndAnswers(t *testing.T) {
+	service := &echoService{}
+	bot, client := newTestBot(t, service)
+
+	client.Push(telegramtest.CallbackUpdate(101, "/edit"))
+	stop := run(t, bot)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.Requested()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+
+	var edit *tgbotapi.EditMessageTextConfig
+	var answer *tgbotapi.CallbackConfig
+	for _, chattable := range client.Requested() {
+		switch c := chattable.(type) {
+		case tgbotapi.EditMessageTextConfig:
+			edit = &c
+		case tgbotapi.CallbackConfig:
+			answer = &c
+		}
+	}
+
+	if edit == nil || edit.ChatID != 101 || edit.Text != "edited" {
+		t.Errorf("edit = %+v, want edit of the callback message in chat 101", edit)
+	}
+	if answer == nil || answer.CallbackQueryID != "callback" || answer.Text != "ok" {
+		t.Errorf("callback answer = %+v, want answer with text %q", answer, "ok")
+	}
+	if len(service.handled) != 1 || service.handled[0] != "/edit" {
+		t.Errorf("handled = %v, want callback data passed as message text", service.handled)
+	}
+}
+
+func TestListenAnswersInlineQuery(t *testing.T) {
+	bot, client := newTestBot(t, &echoService{})
+
+	client.Push(telegramtest.InlineQueryUpdate(101, "week"))
+	stop := run(t, bot)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(client.Requested()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+
+	for _, chattable := range client.Requested() {
+		if config, ok := chattable.(tgbotapi.InlineConfig); ok {
+			if config.InlineQueryID != "inline" || len(config.Results) != 1 {
+				t.Errorf("inline answer = %+v, want one result for query %q", config, "inline")
+			}
+			return
+		}
+	}
+	t.Errorf("no inline answer in requests %v", client.Requested())
+}
+
+func TestShutdownDrainsReceivedUpdates(t *testing.T) {
+	// Медленный обработчик: к остановке большая часть обновлений еще лежит в буфере
+	bot, client := newTestBot(t, &echoService{delay: 20 * time.Millisecond})
+
+	const total = 30
+	for i := 0; i < total; i++ {
+		client.Push(telegramtest.MessageUpdate(int64(200+i%3), fmt.Sprint(i)))
+	}
+
+	stop := run(t, bot)
+	stop()
+
+	if sent := len(client.SentMessages()); sent != total {
+		t.Errorf("sent %d replies after shutdown, want %d", sent, total)
+	}
+}
+
+func TestRateLimitedChatDoesNotDelayOtherChats(t *testing.T) {
+	bot, client := newTestBotWith(t, &echoService{}, func(cfg *config.Telegram) {
+		// Один отправитель на все чаты, в чат — одно сообщение в полсекунды
+		cfg.Workers = 1
+		cfg.Send.Workers = 1
+		cfg.Send.ChatPerMinute = 120
+		cfg.Send.ChatBurst = 1
+	})
+
+	client.Push(
+		telegramtest.MessageUpdate(101, "first"),
+		telegramtest.MessageUpdate(101, "second"),
+		telegramtest.MessageUpdate(102, "other"),
+	)
+
+	stop := run(t, bot)
+	if !client.WaitSent(2, 300*time.Millisecond) {
+		t.Fatalf("sent %v before the chat limit released, want replies in both chats", textsByChat(client.SentMessages()))
+	}
+	got := textsByChat(client.SentMessages())
+	stop()
+
+	if fmt.Sprint(got[101]) != "[first]" || fmt.Sprint(got[102]) != "[other]" {
+		t.Errorf("replies before the chat limit released = %v, want first reply in each chat", got)
+	}
+	if sent := textsByChat(client.SentMessages()); fmt.Sprint(sent[101]) != "[first second]" {
+		t.Errorf("chat 101 replies = %v, want [first second]", sent[101])
+	}
+}
+
+func TestCloseDoesNotWaitForFullQueuePastDeadline(t *testing.T) {
+	bot, client := newTestBotWith(t, &echoService{}, func(cfg *config.Telegram) {
+		// Одно сообщение в чат в секунду: рассылка надолго упирается в заполненную очередь
+		cfg.Send.Workers = 1
+		cfg.Send.QueueSize = 1
+		cfg.Send.ChatPerMinute = 60
+		cfg.Send.ChatBurst = 1
+	})
+
+	broadcast := response.None()
+	for i := 0; i < 10; i++ {
+		broadcast.Add(response.Text{ChatID: 500, Text: fmt.Sprint(i)})
+	}
+	bot.Send(context.Background(), broadcast)
+	if !client.WaitSent(1, time.Second) {
+		t.Fatal("first broadcast message was not sent")
+	}
+	// Рассылка доходит до заполненной очереди и ждет места
+	time.Sleep(100 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	if err := bot.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("Close returned after %v, want about the 200ms deadline", elapsed)
+	}
+}
@@ -0,0 +1,196 @@
+package telegram
+
+import (
+	"azhumania/internal/metrics"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
+)
+
+// job обновление, ожидающее обработки в очереди воркера
+type job struct {
+	ctx      context.Context
+	update   tgbotapi.Update
+	enqueued time.Time
+}
+
+// PoolStats снимок метрик пула обработчиков
+type PoolStats struct {
+	QueueDepth  []int         // текущая длина очереди каждого воркера
+	QueueSize   int           // емкость очереди одного воркера
+	Handled     uint64        // обработано обновлений
+	Throttled   uint64        // сколько раз постановка в очередь ждала свободного места
+	AvgWait     time.Duration // среднее время ожидания в очереди
+	AvgLatency  time.Duration // среднее время обработки
+	MaxLatency  time.Duration // максимальное время обработки
+	LastLatency time.Duration
+}
+
+// workerPool обрабатывает обновления параллельно, сохраняя порядок обновлений одного пользователя:
+// все обновления с одним ключом попадают в очередь одного и того же воркера
+type workerPool struct {
+	queues []chan job
+	handle func(ctx context.Context, update tgbotapi.Update)
+	wg     sync.WaitGroup
+
+	handled     atomic.Uint64
+	throttled   atomic.Uint64
+	waitNanos   atomic.Int64
+	handleNanos atomic.Int64
+	maxNanos    atomic.Int64
+	lastNanos   atomic.Int64
+}
+
+// newWorkerPool создает и запускает пул из workers воркеров с очередями размером queueSize
+func newWorkerPool(workers, queueSize int, handle func(ctx context.Context, update tgbotapi.Update)) *workerPool {
+	p := &workerPool{
+		queues: make([]chan job, workers),
+		handle: handle,
+	}
+
+	for i := range p.queues {
+		p.queues[i] = make(chan job, queueSize)
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+
+	return p
+}
+
+// dispatch ставит обновление в очередь воркера. Если очередь заполнена, вызов блокируется,
+// пока не освободится место или не будет отменен ctx — так нагрузка передается источнику обновлений
+func (p *workerPool) dispatch(ctx context.Context, update tgbotapi.Update) error {
+	queue := p.queues[updateKey(update)%uint64(len(p.queues))]
+	j := job{ctx: ctx, update: update, enqueued: time.Now()}
+
+	select {
+	case queue <- j:
+		metrics.UpdateQueueDepth.Inc()
+		return nil
+	default:
+	}
+
+	p.throttled.Add(1)
+	select {
+	case queue <- j:
+		metrics.UpdateQueueDepth.Inc()
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work обрабатывает очередь одного воркера до ее закрытия
+func (p *workerPool) work(queue <-chan job) {
+	defer p.wg.Done()
+
+	for j := range queue {
+		metrics.UpdateQueueDepth.Dec()
+
+		started := time.Now()
+		p.handle(j.ctx, j.update)
+		latency := time.Since(started)
+
+		metrics.UpdateQueueWait.Observe(started.Sub(j.enqueued).Seconds())
+		metrics.UpdateDuration.Observe(latency.Seconds())
+
+		p.handled.Add(1)
+		p.waitNanos.Add(int64(started.Sub(j.enqueued)))
+		p.handleNanos.Add(int64(latency))
+		p.lastNanos.Store(int64(latency))
+		for {
+			max := p.maxNanos.Load()
+			if int64(latency) <= max || p.maxNanos.CompareAndSwap(max, int64(latency)) {
+				break
+			}
+		}
+	}
+}
+
+// close закрывает очереди и ждет, пока воркеры обработают оставшиеся обновления
+func (p *workerPool) close(ctx context.Context) error {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stats возвращает текущие метрики пула
+func (p *workerPool) stats() PoolStats {
+	stats := PoolStats{
+		QueueDepth:  make([]int, len(p.queues)),
+		QueueSize:   cap(p.queues[0]),
+		Handled:     p.handled.Load(),
+		Throttled:   p.throttled.Load(),
+		MaxLatency:  time.Duration(p.maxNanos.Load()),
+		LastLatency: time.Duration(p.lastNanos.Load()),
+	}
+
+	for i, queue := range p.queues {
+		stats.QueueDepth[i] = len(queue)
+	}
+
+	if stats.Handled > 0 {
+		stats.AvgWait = time.Duration(p.waitNanos.Load() / int64(stats.Handled))
+		stats.AvgLatency = time.Duration(p.handleNanos.Load() / int64(stats.Handled))
+	}
+
+	return stats
+}
+
+// logStats периодически пишет метрики пула в лог, пока не будет отменен ctx
+func (p *workerPool) logStats(ctx context.Context, interval time.Duration, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastHandled uint64
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := p.stats()
+			if stats.Handled == lastHandled {
+				continue
+			}
+			lastHandled = stats.Handled
+
+			logger.Info().
+				Uint64("handled", stats.Handled).
+				Uint64("throttled", stats.Throttled).
+				Ints("queue", stats.QueueDepth).
+				Int("queue_size", stats.QueueSize).
+				Dur("avg_wait", stats.AvgWait).
+				Dur("avg_latency", stats.AvgLatency).
+				Dur("max_latency", stats.MaxLatency).
+				Msg("update pool stats")
+		}
+	}
+}
+
+// updateKey возвращает ключ упорядочивания обновления: ID пользователя, а если его нет — ID чата
+func updateKey(update tgbotapi.Update) uint64 {
+	if user := update.SentFrom(); user != nil {
+		return uint64(user.ID)
+	}
+	if chat := update.FromChat(); chat != nil {
+		return uint64(chat.ID)
+	}
+	return uint64(update.UpdateID)
+}
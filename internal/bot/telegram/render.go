@@ -0,0 +1,157 @@
+package telegram
+
+import (
+	"azhumania/internal/application/response"
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// render выполняет действия ответа в чате chatID. callback — нажатие inline кнопки,
+// на которое нужно ответить, или nil для обычного сообщения. Сообщения ставятся в очередь
+// отправки: в свой чат по порядку, в другие чаты (рассылка, задачи планировщика) — в фоне
+func (t *TelegramBot) render(ctx context.Context, chatID int64, callback *tgbotapi.CallbackQuery, resp *response.Response) {
+	logger := logging.FromContext(ctx, t.logger)
+	answered := false
+
+	var others []outgoing
+	if !resp.Empty() {
+		for _, action := range resp.Actions {
+			if answer, ok := action.(response.CallbackAnswer); ok {
+				if callback == nil || answered {
+					continue
+				}
+				answered = true
+				t.answerCallback(ctx, callback, answer)
+				continue
+			}
+
+			chattable := t.chattable(ctx, chatID, callback, action)
+			if chattable == nil {
+				continue
+			}
+
+			msg := outgoing{chatID: chatID, chattable: chattable, action: actionName(action), logger: logger}
+			if text, ok := action.(response.Text); ok && text.ChatID != 0 {
+				msg.chatID = text.ChatID
+			}
+			if msg.chatID != chatID || chatID == 0 {
+				others = append(others, msg)
+				continue
+			}
+
+			if err := t.sender.enqueue(ctx, msg); err != nil {
+				metrics.TelegramSendErrors.WithLabelValues(msg.action).Inc()
+				logger.Error().Err(err).Str("action", msg.action).Msg("failed to queue response")
+			}
+		}
+	}
+	if len(others) > 0 {
+		t.sender.enqueueAll(others)
+	}
+
+	// Отвечаем на callback, чтобы убрать "часики" у кнопки
+	if callback != nil && !answered {
+		t.answerCallback(ctx, callback, response.CallbackAnswer{})
+	}
+}
+
+// chattable преобразует действие в запрос Telegram Bot API
+func (t *TelegramBot) chattable(ctx context.Context, chatID int64, callback *tgbotapi.CallbackQuery, action response.Action) tgbotapi.Chattable {
+	switch a := action.(type) {
+	case response.Text:
+		if a.ChatID != 0 {
+			chatID = a.ChatID
+		}
+		msg := tgbotapi.NewMessage(chatID, a.Text)
+		msg.ParseMode = string(a.ParseMode)
+		msg.ReplyMarkup = replyMarkup(a.Keyboard)
+		return msg
+
+	case response.Photo:
+		photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: a.File.Name, Bytes: a.File.Data})
+		photo.Caption = a.Caption
+		photo.ParseMode = string(a.ParseMode)
+		photo.ReplyMarkup = replyMarkup(a.Keyboard)
+		return photo
+
+	case response.Document:
+		document := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: a.File.Name, Bytes: a.File.Data})
+		document.Caption = a.Caption
+		document.ParseMode = string(a.ParseMode)
+		document.ReplyMarkup = replyMarkup(a.Keyboard)
+		return document
+
+	case response.Edit:
+		messageID := a.MessageID
+		if messageID == 0 && callback != nil && callback.Message != nil {
+			messageID = callback.Message.MessageID
+		}
+		if messageID == 0 {
+			logging.FromContext(ctx, t.logger).Warn().Msg("skip edit: no message to edit")
+			return nil
+		}
+
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, a.Text)
+		edit.ParseMode = string(a.ParseMode)
+		if a.Keyboard != nil {
+			markup := inlineKeyboard(*a.Keyboard)
+			edit.ReplyMarkup = &markup
+		}
+		return edit
+
+	default:
+		logging.FromContext(ctx, t.logger).Error().Str("action", fmt.Sprintf("%T", action)).Msg("unsupported response action")
+		return nil
+	}
+}
+
+// answerCallback отвечает на нажатие inline кнопки
+func (t *TelegramBot) answerCallback(ctx context.Context, callback *tgbotapi.CallbackQuery, answer response.CallbackAnswer) {
+	config := tgbotapi.NewCallback(callback.ID, answer.Text)
+	config.ShowAlert = answer.ShowAlert
+
+	if err := t.sender.call(ctx, config); err != nil {
+		metrics.TelegramSendErrors.WithLabelValues(actionName(answer)).Inc()
+		logging.FromContext(ctx, t.logger).Error().Err(err).Msg("failed to answer callback")
+	}
+}
+
+// actionName возвращает имя действия для метрик
+func actionName(action response.Action) string {
+	switch action.(type) {
+	case response.Text:
+		return "text"
+	case response.Photo:
+		return "photo"
+	case response.Document:
+		return "document"
+	case response.Edit:
+		return "edit"
+	case response.CallbackAnswer:
+		return "callback_answer"
+	default:
+		return "other"
+	}
+}
+
+// Send отправляет ответ, сформированный не в ответ на обновление, например задачей планировщика.
+// Получатели указываются в response.Text.ChatID
+func (t *TelegramBot) Send(ctx context.Context, resp *response.Response) {
+	t.render(ctx, 0, nil, resp)
+}
+
+// markUnreachable отмечает пользователя, которому Telegram не доставляет сообщения. ID личного
+// чата совпадает с ID пользователя, у групп ID отрицательные — их пропускаем
+func (t *TelegramBot) markUnreachable(chatID int64) {
+	if chatID <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.cfg.UpdateTimeout)
+	defer cancel()
+	t.service.MarkUnreachable(ctx, chatID)
+}
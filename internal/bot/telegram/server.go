@@ -1,31 +1,91 @@
 package telegram
 
 import (
+	"azhumania/internal/config"
 	"azhumania/internal/service"
-	"log"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog"
 )
 
 type TelegramBot struct {
 	apiKey  string
-	bot     *tgbotapi.BotAPI
+	cfg     config.Telegram
+	bot     Client
 	service service.IService
+	sender  *dispatcher
+	logger  *zerolog.Logger
+
+	server         *http.Server // HTTP сервер вебхука, nil в режиме long polling
+	webhookUpdates chan tgbotapi.Update
+	webhookErr     chan error // ошибка HTTP сервера вебхука, из-за которой он остановился
+	closeUpdates   sync.Once  // канал вебхука закрывается один раз: при остановке или после ошибки сервера
+	updates        tgbotapi.UpdatesChannel
+	pool           *workerPool
 }
 
-func New(key string, service service.IService) *TelegramBot {
-	bot, err := tgbotapi.NewBotAPI(key)
+// New подключается к Telegram Bot API и создает бота
+func New(cfg config.Telegram, service service.IService, logger *zerolog.Logger) (*TelegramBot, error) {
+	// Библиотека пишет ошибки long polling через свой логгер, направляем их в общий
+	if err := tgbotapi.SetLogger(botLogger{logger: logger}); err != nil {
+		return nil, err
+	}
+
+	bot, err := tgbotapi.NewBotAPI(cfg.Token)
 	if err != nil {
-		log.Panic(err)
+		return nil, fmt.Errorf("connect to telegram: %w", err)
 	}
 
-	bot.Debug = true
+	logger.Info().Str("account", bot.Self.UserName).Msg("authorized in telegram")
 
-	log.Printf("Authorized on account %s", bot.Self.UserName)
+	return NewWithClient(cfg, bot, service, logger), nil
+}
 
-	return &TelegramBot{
-		apiKey:  key,
-		bot:     bot,
+// NewWithClient создает бота поверх готового клиента, например фейкового из telegramtest
+func NewWithClient(cfg config.Telegram, client Client, service service.IService, logger *zerolog.Logger) *TelegramBot {
+	t := &TelegramBot{
+		apiKey:  cfg.Token,
+		cfg:     cfg,
+		bot:     client,
 		service: service,
+		logger:  logger,
+	}
+	t.sender = newDispatcher(client, cfg.Send, t.markUnreachable, logger)
+
+	return t
+}
+
+// botLogger передает сообщения библиотеки tgbotapi в zerolog
+type botLogger struct {
+	logger *zerolog.Logger
+}
+
+func (l botLogger) Println(v ...interface{}) {
+	l.logger.Warn().Msg(strings.TrimSpace(fmt.Sprintln(v...)))
+}
+
+func (l botLogger) Printf(format string, v ...interface{}) {
+	l.logger.Warn().Msgf(format, v...)
+}
+
+// Ping проверяет доступность Telegram Bot API запросом getMe.
+// Клиент не принимает контекст, поэтому по его отмене ожидание прерывается, а запрос дорабатывает в фоне
+func (t *TelegramBot) Ping(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := t.bot.MakeRequest("getMe", nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
@@ -0,0 +1,160 @@
+// Package telegramtest содержит фейковый клиент Telegram Bot API для запуска бота без сети
+package telegramtest
+
+import (
+	"azhumania/internal/bot/telegram"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var _ telegram.Client = &Client{}
+
+// Request запрос, выполненный через MakeRequest
+type Request struct {
+	Endpoint string
+	Params   tgbotapi.Params
+}
+
+// Client записывает все запросы бота и отдает заранее подготовленные обновления
+type Client struct {
+	// SendFunc позволяет подменить результат Send, например вернуть ошибку
+	SendFunc func(c tgbotapi.Chattable) (tgbotapi.Message, error)
+
+	mu        sync.Mutex
+	sent      []tgbotapi.Chattable
+	requested []tgbotapi.Chattable
+	raw       []Request
+	nextID    int
+	changed   chan struct{}
+
+	updates  chan tgbotapi.Update
+	stopOnce sync.Once
+}
+
+// NewClient создает фейковый клиент с буфером на bufferSize обновлений
+func NewClient(bufferSize int) *Client {
+	return &Client{
+		changed: make(chan struct{}, 1),
+		updates: make(chan tgbotapi.Update, bufferSize),
+	}
+}
+
+// Push добавляет обновления в очередь, которую читает бот
+func (c *Client) Push(updates ...tgbotapi.Update) {
+	for _, update := range updates {
+		c.updates <- update
+	}
+}
+
+// Send записывает отправленное сообщение
+func (c *Client) Send(chattable tgbotapi.Chattable) (tgbotapi.Message, error) {
+	c.mu.Lock()
+	c.sent = append(c.sent, chattable)
+	c.nextID++
+	id := c.nextID
+	c.mu.Unlock()
+	c.notify()
+
+	if c.SendFunc != nil {
+		return c.SendFunc(chattable)
+	}
+
+	return tgbotapi.Message{MessageID: id}, nil
+}
+
+// Request записывает вызов метода API и возвращает успешный ответ
+func (c *Client) Request(chattable tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	c.mu.Lock()
+	c.requested = append(c.requested, chattable)
+	c.mu.Unlock()
+	c.notify()
+
+	return &tgbotapi.APIResponse{Ok: true, Result: []byte("true")}, nil
+}
+
+// MakeRequest записывает вызов метода API и возвращает успешный ответ
+func (c *Client) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	c.mu.Lock()
+	c.raw = append(c.raw, Request{Endpoint: endpoint, Params: params})
+	c.mu.Unlock()
+	c.notify()
+
+	return &tgbotapi.APIResponse{Ok: true, Result: []byte("true")}, nil
+}
+
+// GetUpdatesChan возвращает канал подготовленных обновлений
+func (c *Client) GetUpdatesChan(tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	return c.updates
+}
+
+// StopReceivingUpdates закрывает канал обновлений
+func (c *Client) StopReceivingUpdates() {
+	c.stopOnce.Do(func() {
+		close(c.updates)
+	})
+}
+
+// Sent возвращает копию списка отправленных через Send запросов
+func (c *Client) Sent() []tgbotapi.Chattable {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]tgbotapi.Chattable(nil), c.sent...)
+}
+
+// SentMessages возвращает отправленные текстовые сообщения
+func (c *Client) SentMessages() []tgbotapi.MessageConfig {
+	var messages []tgbotapi.MessageConfig
+	for _, chattable := range c.Sent() {
+		if msg, ok := chattable.(tgbotapi.MessageConfig); ok {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// Requested возвращает копию списка запросов, выполненных через Request
+func (c *Client) Requested() []tgbotapi.Chattable {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]tgbotapi.Chattable(nil), c.requested...)
+}
+
+// RawRequests возвращает копию списка запросов, выполненных через MakeRequest
+func (c *Client) RawRequests() []Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Request(nil), c.raw...)
+}
+
+// WaitSent ждет, пока через Send будет отправлено не меньше n запросов
+func (c *Client) WaitSent(n int, timeout time.Duration) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		c.mu.Lock()
+		count := len(c.sent)
+		c.mu.Unlock()
+		if count >= n {
+			return true
+		}
+
+		select {
+		case <-c.changed:
+		case <-deadline.C:
+			return false
+		}
+	}
+}
+
+func (c *Client) notify() {
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,57 @@
+package telegramtest
+
+import (
+	"sync/atomic"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var updateID atomic.Int64
+
+// MessageUpdate создает обновление с текстовым сообщением пользователя в личном чате
+func MessageUpdate(userID int64, text string) tgbotapi.Update {
+	id := updateID.Add(1)
+	return tgbotapi.Update{
+		UpdateID: int(id),
+		Message: &tgbotapi.Message{
+			MessageID: int(id),
+			From:      user(userID),
+			Chat:      &tgbotapi.Chat{ID: userID, Type: "private"},
+			Text:      text,
+		},
+	}
+}
+
+// CallbackUpdate создает обновление с нажатием inline кнопки
+func CallbackUpdate(userID int64, data string) tgbotapi.Update {
+	id := updateID.Add(1)
+	return tgbotapi.Update{
+		UpdateID: int(id),
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:   "callback",
+			From: user(userID),
+			Message: &tgbotapi.Message{
+				MessageID: int(id),
+				Chat:      &tgbotapi.Chat{ID: userID, Type: "private"},
+			},
+			Data: data,
+		},
+	}
+}
+
+// InlineQueryUpdate создает обновление с inline запросом пользователя
+func InlineQueryUpdate(userID int64, query string) tgbotapi.Update {
+	id := updateID.Add(1)
+	return tgbotapi.Update{
+		UpdateID: int(id),
+		InlineQuery: &tgbotapi.InlineQuery{
+			ID:    "inline",
+			From:  user(userID),
+			Query: query,
+		},
+	}
+}
+
+func user(id int64) *tgbotapi.User {
+	return &tgbotapi.User{ID: id, FirstName: "Test", UserName: "test"}
+}
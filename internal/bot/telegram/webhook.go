@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// secretTokenHeader заголовок, в котором Telegram передает секрет вебхука
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// startWebhook запускает HTTP сервер, принимающий обновления, и регистрирует вебхук в Telegram.
+// Порт занимается до регистрации, чтобы Telegram не слал обновления на неработающий сервер
+func (t *TelegramBot) startWebhook() (tgbotapi.UpdatesChannel, error) {
+	webhookURL, err := url.Parse(t.cfg.Webhook.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse webhook url: %w", err)
+	}
+
+	path := webhookURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	listener, err := net.Listen("tcp", t.cfg.Webhook.Listen)
+	if err != nil {
+		return nil, fmt.Errorf("listen webhook: %w", err)
+	}
+
+	updates := make(chan tgbotapi.Update, t.cfg.QueueSize)
+	t.webhookUpdates = updates
+	t.webhookErr = make(chan error, 1)
+
+	mux := http.NewServeMux()
+	mux.Handle(path, t.webhookHandler(updates))
+
+	t.server = &http.Server{
+		Addr:              t.cfg.Webhook.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		// После штатной остановки канал закрывает stopWebhook, когда завершатся все запросы
+		err := t.server.Serve(listener)
+		if err == nil || errors.Is(err, http.ErrServerClosed) {
+			return
+		}
+
+		// Сервер упал: дожидаемся запросов, которые еще пишут в канал, и только потом закрываем
+		// его, чтобы Listen завершился и вернул ошибку
+		t.webhookErr <- fmt.Errorf("serve webhook: %w", err)
+		_ = t.server.Shutdown(context.Background())
+		t.closeWebhookUpdates()
+	}()
+
+	params := tgbotapi.Params{"url": webhookURL.String()}
+	params.AddNonEmpty("secret_token", t.cfg.Webhook.SecretToken)
+	if _, err := t.bot.MakeRequest("setWebhook", params); err != nil {
+		t.server.Close()
+		return nil, fmt.Errorf("set webhook: %w", err)
+	}
+
+	t.logger.Info().Str("url", webhookURL.Redacted()).Str("listen", t.cfg.Webhook.Listen).Msg("webhook registered")
+
+	return updates, nil
+}
+
+// webhookHandler принимает обновления от Telegram и передает их в общий конвейер обработки
+func (t *TelegramBot) webhookHandler(updates chan<- tgbotapi.Update) http.HandlerFunc {
+	secret := []byte(t.cfg.Webhook.SecretToken)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), secret) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case updates <- update:
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+			// Telegram повторит доставку, если не получит ответ
+		}
+	}
+}
+
+// stopWebhook снимает вебхук в Telegram и останавливает HTTP сервер
+func (t *TelegramBot) stopWebhook(ctx context.Context) error {
+	if _, err := t.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		t.logger.Error().Err(err).Msg("failed to delete webhook")
+	}
+
+	if err := t.server.Shutdown(ctx); err != nil {
+		return err
+	}
+	t.closeWebhookUpdates()
+
+	return nil
+}
+
+// closeWebhookUpdates закрывает канал обновлений вебхука, когда обработчики запросов уже завершились
+func (t *TelegramBot) closeWebhookUpdates() {
+	t.closeUpdates.Do(func() {
+		close(t.webhookUpdates)
+	})
+}
+
+// receiveErr возвращает ошибку, из-за которой закрылся канал обновлений, nil при штатной остановке
+func (t *TelegramBot) receiveErr() error {
+	select {
+	case err := <-t.webhookErr:
+		return err
+	default:
+		return nil
+	}
+}
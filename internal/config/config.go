@@ -0,0 +1,306 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Config содержит всю конфигурацию приложения
+type Config struct {
+	Telegram Telegram `yaml:"telegram"`
+	Postgres Postgres `yaml:"postgres"`
+	Redis    Redis    `yaml:"redis"`
+
+	RateLimit RateLimit `yaml:"rate_limit"`
+	HTTP      HTTP      `yaml:"http"`
+	Log       Log       `yaml:"log"`
+
+	// Admins Telegram ID администраторов, которым доступны команды /admin
+	Admins []int64 `yaml:"admins"`
+
+	// ShutdownTimeout ограничивает время на завершение обработки и закрытие соединений
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// Режимы получения обновлений от Telegram
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// Telegram содержит настройки Telegram бота
+type Telegram struct {
+	Token         string        `yaml:"token"`
+	Mode          string        `yaml:"mode"`
+	Webhook       Webhook       `yaml:"webhook"`
+	UpdateTimeout time.Duration `yaml:"update_timeout"` // максимальное время обработки одного обновления
+	Workers       int           `yaml:"workers"`        // количество параллельных обработчиков обновлений
+	QueueSize     int           `yaml:"queue_size"`     // длина очереди одного обработчика
+	Send          Send          `yaml:"send"`
+}
+
+// Webhook содержит настройки приема обновлений через вебхук
+type Webhook struct {
+	URL         string `yaml:"url"`          // публичный адрес, который регистрируется в Telegram
+	Listen      string `yaml:"listen"`       // адрес HTTP сервера, например ":8443"
+	SecretToken string `yaml:"secret_token"` // значение заголовка X-Telegram-Bot-Api-Secret-Token
+}
+
+// Send содержит настройки отправки исходящих сообщений. Telegram ограничивает частоту
+// сообщений: около 30 в секунду на бота и около одного в секунду в один чат
+type Send struct {
+	Workers       int           `yaml:"workers"`         // количество параллельных отправителей
+	QueueSize     int           `yaml:"queue_size"`      // длина очереди одного отправителя
+	PerSecond     int           `yaml:"per_second"`      // сообщений в секунду на всего бота
+	ChatPerMinute int           `yaml:"chat_per_minute"` // сообщений в минуту в один чат
+	ChatBurst     int           `yaml:"chat_burst"`      // сообщений в один чат подряд
+	MaxRetries    int           `yaml:"max_retries"`     // повторы после сетевой ошибки; 429 повторяется без ограничения
+	RetryBackoff  time.Duration `yaml:"retry_backoff"`   // пауза перед первым повтором, дальше удваивается
+}
+
+// Postgres содержит настройки подключения к PostgreSQL
+type Postgres struct {
+	DSN string `yaml:"dsn"`
+}
+
+// Redis содержит настройки подключения к Redis
+type Redis struct {
+	Addr     string `yaml:"addr"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+// HTTP содержит настройки HTTP сервера с REST API и метриками
+type HTTP struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"`
+	API     bool   `yaml:"api"`     // обслуживать REST API по /api/
+	Metrics bool   `yaml:"metrics"` // отдавать метрики Prometheus по /metrics
+	// PublicURL адрес, по которому сервер доступен из интернета. Нужен для графиков в inline
+	// режиме: Telegram скачивает картинку по ссылке /charts/...
+	PublicURL string `yaml:"public_url"`
+}
+
+// Форматы вывода логов
+const (
+	LogFormatJSON    = "json"
+	LogFormatConsole = "console"
+)
+
+// Log содержит настройки логирования
+type Log struct {
+	Level  string `yaml:"level"`  // debug, info, warn или error
+	Format string `yaml:"format"` // json — для сбора логов, console — для чтения человеком
+	Redact bool   `yaml:"redact"` // маскировать токены и персональные данные пользователей
+}
+
+// Хранилища состояния ограничителя частоты запросов
+const (
+	RateLimitMemory = "memory"
+	RateLimitRedis  = "redis"
+)
+
+// RateLimit содержит настройки ограничения частоты сообщений от пользователей
+type RateLimit struct {
+	Enabled            bool          `yaml:"enabled"`
+	Backend            string        `yaml:"backend"` // memory — в памяти процесса, redis — общий для экземпляров
+	MessagesPerMinute  int           `yaml:"messages_per_minute"`
+	Burst              int           `yaml:"burst"`
+	ExpensivePerMinute int           `yaml:"expensive_per_minute"` // лимит для тяжелых команд и пересчета inline результатов
+	ExpensiveBurst     int           `yaml:"expensive_burst"`
+	NoticeWindow       time.Duration `yaml:"notice_window"`  // как часто можно напоминать о превышении лимита
+	APIPerMinute       int           `yaml:"api_per_minute"` // запросов REST API в минуту с одним токеном
+	APIBurst           int           `yaml:"api_burst"`
+}
+
+// Default возвращает конфигурацию со значениями по умолчанию
+func Default() *Config {
+	return &Config{
+		Telegram: Telegram{
+			Mode: ModePolling,
+			Webhook: Webhook{
+				Listen: ":8443",
+			},
+			UpdateTimeout: 30 * time.Second,
+			Workers:       8,
+			QueueSize:     64,
+			Send: Send{
+				Workers:       4,
+				QueueSize:     256,
+				PerSecond:     25,
+				ChatPerMinute: 30,
+				ChatBurst:     10,
+				MaxRetries:    3,
+				RetryBackoff:  time.Second,
+			},
+		},
+		Redis: Redis{
+			Addr:     "localhost:6379",
+			Username: "default",
+		},
+		RateLimit: RateLimit{
+			Enabled:            true,
+			Backend:            RateLimitMemory,
+			MessagesPerMinute:  30,
+			Burst:              10,
+			ExpensivePerMinute: 6,
+			ExpensiveBurst:     2,
+			NoticeWindow:       time.Minute,
+			APIPerMinute:       60,
+			APIBurst:           20,
+		},
+		HTTP: HTTP{
+			Listen:  ":8080",
+			API:     true,
+			Metrics: true,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: LogFormatJSON,
+			Redact: true,
+		},
+		ShutdownTimeout: 15 * time.Second,
+	}
+}
+
+// Validate проверяет, что все обязательные поля заполнены
+func (c *Config) Validate() error {
+	return c.validate(true)
+}
+
+// validate проверяет конфигурацию. Настройки Telegram не нужны адаптерам без бота
+func (c *Config) validate(requireTelegram bool) error {
+	var errs []error
+
+	if requireTelegram {
+		errs = append(errs, c.Telegram.validate()...)
+	}
+	if c.Postgres.DSN == "" {
+		errs = append(errs, requiredError("postgres.dsn"))
+	}
+	if c.Redis.Addr == "" {
+		errs = append(errs, requiredError("redis.addr"))
+	}
+	if c.Redis.DB < 0 {
+		errs = append(errs, fmt.Errorf("redis.db must not be negative, got %d", c.Redis.DB))
+	}
+
+	if c.RateLimit.Enabled {
+		errs = append(errs, c.RateLimit.validate()...)
+	}
+	if c.HTTP.Enabled && c.HTTP.Listen == "" {
+		errs = append(errs, requiredError("http.listen"))
+	}
+	if c.HTTP.PublicURL != "" && !c.HTTP.Enabled {
+		errs = append(errs, errors.New("http.public_url requires http.enabled"))
+	}
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level must be one of debug, info, warn, error, got %q", c.Log.Level))
+	}
+	if c.Log.Format != LogFormatJSON && c.Log.Format != LogFormatConsole {
+		errs = append(errs, fmt.Errorf("log.format must be %q or %q, got %q", LogFormatJSON, LogFormatConsole, c.Log.Format))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("shutdown_timeout must be positive, got %s", c.ShutdownTimeout))
+	}
+
+	return errors.Join(errs...)
+}
+
+// requiredError формирует ошибку об отсутствующем обязательном поле с подсказкой, где его задать
+func requiredError(key string) error {
+	return fmt.Errorf("%s is required (set %s, %s_FILE, -%s or %q in the config file)",
+		key, envName(key), envName(key), flagName(key), key)
+}
+
+func (t Telegram) validate() []error {
+	var errs []error
+
+	if t.Token == "" {
+		errs = append(errs, requiredError("telegram.token"))
+	}
+	switch t.Mode {
+	case ModePolling:
+	case ModeWebhook:
+		if t.Webhook.URL == "" {
+			errs = append(errs, requiredError("telegram.webhook.url"))
+		}
+		if t.Webhook.SecretToken == "" {
+			errs = append(errs, requiredError("telegram.webhook.secret_token"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("telegram.mode must be %q or %q, got %q", ModePolling, ModeWebhook, t.Mode))
+	}
+	if t.UpdateTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("telegram.update_timeout must be positive, got %s", t.UpdateTimeout))
+	}
+	if t.Workers <= 0 {
+		errs = append(errs, fmt.Errorf("telegram.workers must be positive, got %d", t.Workers))
+	}
+	if t.QueueSize <= 0 {
+		errs = append(errs, fmt.Errorf("telegram.queue_size must be positive, got %d", t.QueueSize))
+	}
+	errs = append(errs, t.Send.validate()...)
+
+	return errs
+}
+
+func (s Send) validate() []error {
+	var errs []error
+
+	positive := []struct {
+		key   string
+		value int
+	}{
+		{"telegram.send.workers", s.Workers},
+		{"telegram.send.queue_size", s.QueueSize},
+		{"telegram.send.per_second", s.PerSecond},
+		{"telegram.send.chat_per_minute", s.ChatPerMinute},
+		{"telegram.send.chat_burst", s.ChatBurst},
+	}
+	for _, field := range positive {
+		if field.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", field.key, field.value))
+		}
+	}
+	if s.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("telegram.send.max_retries must not be negative, got %d", s.MaxRetries))
+	}
+	if s.RetryBackoff <= 0 {
+		errs = append(errs, fmt.Errorf("telegram.send.retry_backoff must be positive, got %s", s.RetryBackoff))
+	}
+
+	return errs
+}
+
+func (r RateLimit) validate() []error {
+	var errs []error
+
+	if r.Backend != RateLimitMemory && r.Backend != RateLimitRedis {
+		errs = append(errs, fmt.Errorf("rate_limit.backend must be %q or %q, got %q", RateLimitMemory, RateLimitRedis, r.Backend))
+	}
+	for _, f := range []struct {
+		key   string
+		value int
+	}{
+		{"rate_limit.messages_per_minute", r.MessagesPerMinute},
+		{"rate_limit.burst", r.Burst},
+		{"rate_limit.expensive_per_minute", r.ExpensivePerMinute},
+		{"rate_limit.expensive_burst", r.ExpensiveBurst},
+		{"rate_limit.api_per_minute", r.APIPerMinute},
+		{"rate_limit.api_burst", r.APIBurst},
+	} {
+		if f.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", f.key, f.value))
+		}
+	}
+	if r.NoticeWindow <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit.notice_window must be positive, got %s", r.NoticeWindow))
+	}
+
+	return errs
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix префикс всех переменных окружения приложения
+const envPrefix = "AZHUMANIA_"
+
+// field описывает одно настраиваемое поле конфигурации
+type field struct {
+	key   string // ключ в файле конфигурации, например "telegram.token"
+	usage string
+	ptr   any
+}
+
+// fields возвращает список полей, которые можно задать через окружение и флаги
+func (c *Config) fields() []field {
+	return []field{
+		{key: "telegram.token", usage: "Telegram bot API token", ptr: &c.Telegram.Token},
+		{key: "telegram.mode", usage: "how to receive updates: polling or webhook", ptr: &c.Telegram.Mode},
+		{key: "telegram.webhook.url", usage: "public webhook URL registered in Telegram", ptr: &c.Telegram.Webhook.URL},
+		{key: "telegram.webhook.listen", usage: "webhook HTTP server address", ptr: &c.Telegram.Webhook.Listen},
+		{key: "telegram.webhook.secret_token", usage: "secret expected in X-Telegram-Bot-Api-Secret-Token", ptr: &c.Telegram.Webhook.SecretToken},
+		{key: "telegram.update_timeout", usage: "maximum time to handle a single update", ptr: &c.Telegram.UpdateTimeout},
+		{key: "telegram.workers", usage: "number of concurrent update handlers", ptr: &c.Telegram.Workers},
+		{key: "telegram.queue_size", usage: "queue length per update handler", ptr: &c.Telegram.QueueSize},
+		{key: "telegram.send.workers", usage: "number of concurrent message senders", ptr: &c.Telegram.Send.Workers},
+		{key: "telegram.send.queue_size", usage: "queue length per message sender", ptr: &c.Telegram.Send.QueueSize},
+		{key: "telegram.send.per_second", usage: "outgoing messages per second for the whole bot", ptr: &c.Telegram.Send.PerSecond},
+		{key: "telegram.send.chat_per_minute", usage: "outgoing messages per minute to a single chat", ptr: &c.Telegram.Send.ChatPerMinute},
+		{key: "telegram.send.chat_burst", usage: "outgoing messages to a single chat at once", ptr: &c.Telegram.Send.ChatBurst},
+		{key: "telegram.send.max_retries", usage: "retries after a network error; 429 is retried after retry_after without limit", ptr: &c.Telegram.Send.MaxRetries},
+		{key: "telegram.send.retry_backoff", usage: "delay before the first retry, doubled for each next one", ptr: &c.Telegram.Send.RetryBackoff},
+		{key: "postgres.dsn", usage: "PostgreSQL connection string", ptr: &c.Postgres.DSN},
+		{key: "redis.addr", usage: "Redis address (host:port)", ptr: &c.Redis.Addr},
+		{key: "redis.username", usage: "Redis username", ptr: &c.Redis.Username},
+		{key: "redis.password", usage: "Redis password", ptr: &c.Redis.Password},
+		{key: "redis.db", usage: "Redis database number", ptr: &c.Redis.DB},
+		{key: "rate_limit.enabled", usage: "limit how often a user can message the bot", ptr: &c.RateLimit.Enabled},
+		{key: "rate_limit.backend", usage: "rate limiter storage: memory or redis", ptr: &c.RateLimit.Backend},
+		{key: "rate_limit.messages_per_minute", usage: "messages per minute allowed per user", ptr: &c.RateLimit.MessagesPerMinute},
+		{key: "rate_limit.burst", usage: "messages a user can send at once", ptr: &c.RateLimit.Burst},
+		{key: "rate_limit.expensive_per_minute", usage: "expensive commands per minute allowed per user", ptr: &c.RateLimit.ExpensivePerMinute},
+		{key: "rate_limit.expensive_burst", usage: "expensive commands a user can send at once", ptr: &c.RateLimit.ExpensiveBurst},
+		{key: "rate_limit.notice_window", usage: "minimum interval between throttle notices", ptr: &c.RateLimit.NoticeWindow},
+		{key: "rate_limit.api_per_minute", usage: "REST API requests per minute allowed per token", ptr: &c.RateLimit.APIPerMinute},
+		{key: "rate_limit.api_burst", usage: "REST API requests a token can make at once", ptr: &c.RateLimit.APIBurst},
+		{key: "http.enabled", usage: "start the HTTP server", ptr: &c.HTTP.Enabled},
+		{key: "http.listen", usage: "HTTP server address", ptr: &c.HTTP.Listen},
+		{key: "http.api", usage: "serve the REST API under /api/", ptr: &c.HTTP.API},
+		{key: "http.metrics", usage: "serve Prometheus metrics at /metrics", ptr: &c.HTTP.Metrics},
+		{key: "http.public_url", usage: "public HTTP server address for inline mode charts", ptr: &c.HTTP.PublicURL},
+		{key: "log.level", usage: "log level: debug, info, warn or error", ptr: &c.Log.Level},
+		{key: "log.format", usage: "log format: json or console", ptr: &c.Log.Format},
+		{key: "log.redact", usage: "mask tokens and personal data in logs", ptr: &c.Log.Redact},
+		{key: "admins", usage: "comma-separated Telegram IDs of bot admins", ptr: &c.Admins},
+		{key: "shutdown_timeout", usage: "time allowed for graceful shutdown", ptr: &c.ShutdownTimeout},
+	}
+}
+
+// Option настраивает загрузку конфигурации
+type Option func(*options)
+
+type options struct {
+	flags           []func(fs *flag.FlagSet)
+	requireTelegram bool
+}
+
+// WithFlags регистрирует собственные флаги программы в общем наборе флагов конфигурации
+func WithFlags(register func(fs *flag.FlagSet)) Option {
+	return func(o *options) {
+		o.flags = append(o.flags, register)
+	}
+}
+
+// WithoutTelegram не требует настроек Telegram, например для локального CLI адаптера
+func WithoutTelegram() Option {
+	return func(o *options) {
+		o.requireTelegram = false
+	}
+}
+
+// Load собирает конфигурацию из значений по умолчанию, файла, переменных окружения и флагов.
+// Каждый следующий источник перекрывает предыдущий. Путь к файлу задается флагом -config
+// или переменной AZHUMANIA_CONFIG.
+func Load(args []string, opts ...Option) (*Config, error) {
+	o := options{requireTelegram: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cfg := Default()
+	fields := cfg.fields()
+
+	fs := flag.NewFlagSet("azhumania", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(envPrefix+"CONFIG"), "path to YAML config file")
+	for _, f := range fields {
+		if _, ok := f.ptr.(*bool); ok {
+			fs.Bool(flagName(f.key), false, f.usage)
+			continue
+		}
+		fs.String(flagName(f.key), "", f.usage)
+	}
+	for _, register := range o.flags {
+		register(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *configPath != "" {
+		if err := loadFile(*configPath, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, f := range fields {
+		if err := applyEnv(f); err != nil {
+			return nil, err
+		}
+	}
+
+	byFlag := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byFlag[flagName(f.key)] = f
+	}
+
+	var flagErr error
+	fs.Visit(func(fl *flag.Flag) {
+		f, ok := byFlag[fl.Name]
+		if !ok || flagErr != nil {
+			return
+		}
+		if err := f.set(fl.Value.String()); err != nil {
+			flagErr = fmt.Errorf("flag -%s: %w", fl.Name, err)
+		}
+	})
+	if flagErr != nil {
+		return nil, flagErr
+	}
+
+	if err := cfg.validate(o.requireTelegram); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// loadFile читает YAML файл конфигурации поверх значений по умолчанию
+func loadFile(path string, cfg *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open config file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv применяет значение поля из переменной окружения или из файла, указанного в <NAME>_FILE
+func applyEnv(f field) error {
+	name := envName(f.key)
+
+	value, hasValue := os.LookupEnv(name)
+	path, hasFile := os.LookupEnv(name + "_FILE")
+
+	switch {
+	case hasValue && hasFile:
+		return fmt.Errorf("both %s and %s_FILE are set, use only one", name, name)
+	case hasFile:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s_FILE: %w", name, err)
+		}
+		value = strings.TrimRight(string(data), "\r\n")
+	case !hasValue:
+		return nil
+	}
+
+	if err := f.set(value); err != nil {
+		return fmt.Errorf("env %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// set разбирает строковое значение в соответствии с типом поля
+func (f field) set(value string) error {
+	switch p := f.ptr.(type) {
+	case *string:
+		*p = value
+	case *int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: expected integer, got %q", f.key, value)
+		}
+		*p = v
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: expected boolean, got %q", f.key, value)
+		}
+		*p = v
+	case *time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: expected duration (e.g. 30s), got %q", f.key, value)
+		}
+		*p = v
+	case *[]int64:
+		var list []int64
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			v, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return fmt.Errorf("%s: expected comma-separated integers, got %q", f.key, value)
+			}
+			list = append(list, v)
+		}
+		*p = list
+	default:
+		return fmt.Errorf("%s: unsupported field type %T", f.key, f.ptr)
+	}
+
+	return nil
+}
+
+// envName возвращает имя переменной окружения для ключа, например AZHUMANIA_TELEGRAM_TOKEN
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// flagName возвращает имя флага для ключа, например telegram-token
+func flagName(key string) string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(key)
+}
@@ -0,0 +1,249 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile создает временный файл с содержимым content и возвращает путь к нему
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+postgres:
+  dsn: postgres://file
+redis:
+  addr: file:6379
+  db: 1
+telegram:
+  workers: 2
+log:
+  level: debug
+`)
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		args  []string
+		check func(cfg *Config) error
+	}{
+		{
+			name: "значения по умолчанию",
+			env:  map[string]string{"AZHUMANIA_POSTGRES_DSN": "postgres://env"},
+			check: func(cfg *Config) error {
+				if cfg.Redis.Addr != "localhost:6379" || cfg.Telegram.Workers != 8 || cfg.ShutdownTimeout != 15*time.Second {
+					return fmt.Errorf("redis.addr %q, telegram.workers %d, shutdown_timeout %v, want defaults",
+						cfg.Redis.Addr, cfg.Telegram.Workers, cfg.ShutdownTimeout)
+				}
+				return nil
+			},
+		},
+		{
+			name: "файл перекрывает значения по умолчанию",
+			args: []string{"-config", path},
+			check: func(cfg *Config) error {
+				if cfg.Postgres.DSN != "postgres://file" || cfg.Redis.Addr != "file:6379" || cfg.Telegram.Workers != 2 {
+					return fmt.Errorf("postgres.dsn %q, redis.addr %q, telegram.workers %d, want values from file",
+						cfg.Postgres.DSN, cfg.Redis.Addr, cfg.Telegram.Workers)
+				}
+				// Поля, которых нет в файле, остаются по умолчанию
+				if cfg.Telegram.QueueSize != 64 {
+					return fmt.Errorf("telegram.queue_size %d, want default 64", cfg.Telegram.QueueSize)
+				}
+				return nil
+			},
+		},
+		{
+			name: "путь к файлу из окружения",
+			env:  map[string]string{"AZHUMANIA_CONFIG": path},
+			check: func(cfg *Config) error {
+				if cfg.Postgres.DSN != "postgres://file" {
+					return fmt.Errorf("postgres.dsn %q, want value from file", cfg.Postgres.DSN)
+				}
+				return nil
+			},
+		},
+		{
+			name: "окружение перекрывает файл",
+			env:  map[string]string{"AZHUMANIA_REDIS_ADDR": "env:6379", "AZHUMANIA_TELEGRAM_WORKERS": "3"},
+			args: []string{"-config", path},
+			check: func(cfg *Config) error {
+				if cfg.Redis.Addr != "env:6379" || cfg.Telegram.Workers != 3 || cfg.Redis.DB != 1 {
+					return fmt.Errorf("redis.addr %q, telegram.workers %d, redis.db %d, want env values over file",
+						cfg.Redis.Addr, cfg.Telegram.Workers, cfg.Redis.DB)
+				}
+				return nil
+			},
+		},
+		{
+			name: "флаг перекрывает окружение",
+			env:  map[string]string{"AZHUMANIA_REDIS_ADDR": "env:6379", "AZHUMANIA_LOG_LEVEL": "warn"},
+			args: []string{"-config", path, "-redis-addr", "flag:6379", "-shutdown-timeout", "5s"},
+			check: func(cfg *Config) error {
+				if cfg.Redis.Addr != "flag:6379" || cfg.ShutdownTimeout != 5*time.Second || cfg.Log.Level != "warn" {
+					return fmt.Errorf("redis.addr %q, shutdown_timeout %v, log.level %q, want flags over env",
+						cfg.Redis.Addr, cfg.ShutdownTimeout, cfg.Log.Level)
+				}
+				return nil
+			},
+		},
+		{
+			name: "булевы флаги перекрывают окружение",
+			env:  map[string]string{"AZHUMANIA_POSTGRES_DSN": "postgres://env", "AZHUMANIA_RATE_LIMIT_ENABLED": "true"},
+			args: []string{"-rate-limit-enabled=false", "-log-redact"},
+			check: func(cfg *Config) error {
+				if cfg.RateLimit.Enabled || !cfg.Log.Redact {
+					return fmt.Errorf("rate_limit.enabled %v, log.redact %v, want false and true", cfg.RateLimit.Enabled, cfg.Log.Redact)
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, err := Load(tt.args, WithoutTelegram())
+			if err != nil {
+				t.Fatalf("Load error = %v", err)
+			}
+			if err := tt.check(cfg); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	secret := writeFile(t, "token", "123:secret\n")
+	t.Setenv("AZHUMANIA_TELEGRAM_TOKEN_FILE", secret)
+	t.Setenv("AZHUMANIA_POSTGRES_DSN", "postgres://env")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load error = %v", err)
+	}
+	// Перевод строки в конце файла отбрасывается
+	if cfg.Telegram.Token != "123:secret" {
+		t.Errorf("telegram.token = %q, want value from file", cfg.Telegram.Token)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	secret := writeFile(t, "token", "123:secret")
+	unknown := writeFile(t, "config.yaml", "postgres:\n  dns: postgres://typo\n")
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{
+			name: "переменная и _FILE одновременно",
+			env:  map[string]string{"AZHUMANIA_TELEGRAM_TOKEN": "123:env", "AZHUMANIA_TELEGRAM_TOKEN_FILE": secret},
+			want: "both AZHUMANIA_TELEGRAM_TOKEN and AZHUMANIA_TELEGRAM_TOKEN_FILE are set",
+		},
+		{
+			name: "_FILE указывает на несуществующий файл",
+			env:  map[string]string{"AZHUMANIA_POSTGRES_DSN_FILE": filepath.Join(t.TempDir(), "missing")},
+			want: "read AZHUMANIA_POSTGRES_DSN_FILE",
+		},
+		{
+			name: "неизвестное поле в файле",
+			args: []string{"-config", unknown},
+			want: "field dns not found",
+		},
+		{
+			name: "число в окружении",
+			env:  map[string]string{"AZHUMANIA_TELEGRAM_WORKERS": "many"},
+			want: "env AZHUMANIA_TELEGRAM_WORKERS: telegram.workers: expected integer",
+		},
+		{
+			name: "длительность во флаге",
+			args: []string{"-shutdown-timeout", "15"},
+			want: "flag -shutdown-timeout: shutdown_timeout: expected duration",
+		},
+		{
+			name: "обязательное поле",
+			want: "postgres.dsn is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, err := Load(tt.args, WithoutTelegram())
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadAdmins(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"42", "[42]"},
+		{"42,7", "[42 7]"},
+		{" 42 , 7 ,", "[42 7]"},
+		{"", "[]"},
+		{"-100123", "[-100123]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("AZHUMANIA_POSTGRES_DSN", "postgres://env")
+			t.Setenv("AZHUMANIA_ADMINS", tt.value)
+
+			cfg, err := Load(nil, WithoutTelegram())
+			if err != nil {
+				t.Fatalf("Load error = %v", err)
+			}
+			if got := fmt.Sprint(cfg.Admins); got != tt.want {
+				t.Errorf("admins = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("не число", func(t *testing.T) {
+		t.Setenv("AZHUMANIA_POSTGRES_DSN", "postgres://env")
+
+		_, err := Load([]string{"-admins", "42,abc"}, WithoutTelegram())
+		if err == nil || !strings.Contains(err.Error(), "admins: expected comma-separated integers") {
+			t.Errorf("Load error = %v, want comma-separated integers error", err)
+		}
+	})
+
+	t.Run("список в файле", func(t *testing.T) {
+		path := writeFile(t, "config.yaml", "postgres:\n  dsn: postgres://file\nadmins: [42, 7]\n")
+
+		cfg, err := Load([]string{"-config", path}, WithoutTelegram())
+		if err != nil {
+			t.Fatalf("Load error = %v", err)
+		}
+		if got := fmt.Sprint(cfg.Admins); got != "[42 7]" {
+			t.Errorf("admins = %s, want [42 7]", got)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Доменные ошибки
 var (
@@ -10,4 +13,29 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrInvalidPushupCount = errors.New("invalid pushup count")
 	ErrPushupCountTooHigh = errors.New("pushup count too high")
+	ErrInvalidToken       = errors.New("invalid API token")
+	ErrCannotBanAdmin     = errors.New("cannot ban an admin")
+	ErrNoPendingBroadcast = errors.New("no pending broadcast")
+	ErrProgramNotFound    = errors.New("program not found")
+	ErrInitialMaxRequired = errors.New("initial max is required")
+	ErrNotEnrolled        = errors.New("not enrolled in a program")
+	ErrInvalidRestTimer   = errors.New("invalid rest timer interval")
+	ErrInvalidTimezone    = errors.New("invalid timezone")
+	ErrInvalidDailyGoal   = errors.New("invalid daily goal")
+	ErrRateLimited        = errors.New("rate limit exceeded")
 )
+
+// ApproachError ошибка одного из подходов, добавляемых вместе. Index считается с 0
+type ApproachError struct {
+	Index int
+	Count int
+	Err   error
+}
+
+func (e *ApproachError) Error() string {
+	return fmt.Sprintf("approach %d (%d): %v", e.Index+1, e.Count, e.Err)
+}
+
+func (e *ApproachError) Unwrap() error {
+	return e.Err
+}
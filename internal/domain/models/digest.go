@@ -0,0 +1,116 @@
+package models
+
+import "time"
+
+// DigestHour час по времени пользователя, с которого в последний день недели отправляется сводка
+const DigestHour = 19
+
+// WeeklyDigest сводка за неделю пользователя с первого дня недели из его настройки
+type WeeklyDigest struct {
+	UserID        int64
+	WeekStart     time.Time // первый день недели
+	Days          [7]int    // отжимания по дням, с WeekStart
+	PreviousTotal int       // отжимания за предыдущую неделю
+	DailyGoal     int       // цель на день, 0 — не задана
+	Streak        int       // серия дней подряд, которая продолжается к концу недели, 0 — серия прервалась
+}
+
+// NewWeeklyDigest собирает сводку за неделю weekStart из сессий этой и предыдущей недели.
+// Дата сессии — день по поясу, который был у пользователя при записи, поэтому подходы
+// раскладываются по дням по своему времени в текущем поясе loc. Подходы без времени остаются
+// в дне сессии
+func NewWeeklyDigest(userID int64, weekStart time.Time, sessions []*PushupSession, dailyGoal int, loc *time.Location) *WeeklyDigest {
+	digest := &WeeklyDigest{
+		UserID:    userID,
+		WeekStart: weekStart,
+		DailyGoal: dailyGoal,
+	}
+
+	previousStart := weekStart.AddDate(0, 0, -7)
+	for _, session := range sessions {
+		for _, approach := range session.Approaches {
+			day := session.Date.Truncate(24 * time.Hour)
+			if !approach.CreatedAt.IsZero() {
+				day = LocalDate(approach.CreatedAt, loc)
+			}
+
+			switch {
+			case day.Before(previousStart):
+			case day.Before(weekStart):
+				digest.PreviousTotal += approach.Count
+			default:
+				if i := int(day.Sub(weekStart) / (24 * time.Hour)); i < len(digest.Days) {
+					digest.Days[i] += approach.Count
+				}
+			}
+		}
+	}
+
+	return digest
+}
+
+// DigestWeek возвращает первый день недели, сводку за которую пора отправить в момент now
+// по времени loc, если неделя начинается с дня first: в ее последний день начиная с DigestHour
+func DigestWeek(now time.Time, loc *time.Location, first time.Weekday) (time.Time, bool) {
+	local := now.In(loc)
+	if local.Weekday() != (first+6)%7 || local.Hour() < DigestHour {
+		return time.Time{}, false
+	}
+
+	return WeekOf(LocalDate(now, loc), first).From, true
+}
+
+// Total возвращает отжимания за неделю
+func (d *WeeklyDigest) Total() int {
+	total := 0
+	for _, count := range d.Days {
+		total += count
+	}
+	return total
+}
+
+// TrainingDays возвращает количество дней с отжиманиями
+func (d *WeeklyDigest) TrainingDays() int {
+	days := 0
+	for _, count := range d.Days {
+		if count > 0 {
+			days++
+		}
+	}
+	return days
+}
+
+// BestDay возвращает лучший день недели (индекс с WeekStart) и отжимания в нем
+func (d *WeeklyDigest) BestDay() (int, int) {
+	best := 0
+	for i, count := range d.Days {
+		if count > d.Days[best] {
+			best = i
+		}
+	}
+	return best, d.Days[best]
+}
+
+// GoalDays возвращает количество дней, в которые выполнена дневная цель
+func (d *WeeklyDigest) GoalDays() int {
+	if d.DailyGoal <= 0 {
+		return 0
+	}
+
+	days := 0
+	for _, count := range d.Days {
+		if count >= d.DailyGoal {
+			days++
+		}
+	}
+	return days
+}
+
+// Change возвращает изменение к предыдущей неделе в процентах. false, если на предыдущей
+// неделе отжиманий не было и сравнивать не с чем
+func (d *WeeklyDigest) Change() (float64, bool) {
+	if d.PreviousTotal == 0 {
+		return 0, false
+	}
+	return float64(d.Total()-d.PreviousTotal) / float64(d.PreviousTotal) * 100, true
+}
@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Period период статистики: дни с From по To включительно. Даты — полночь UTC, как даты сессий
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// NewPeriod создает период с from по to включительно, время дня отбрасывается
+func NewPeriod(from, to time.Time) Period {
+	return Period{From: from.Truncate(24 * time.Hour), To: to.Truncate(24 * time.Hour)}
+}
+
+// WeekOf возвращает неделю, в которую входит day, начиная с дня first
+func WeekOf(day time.Time, first time.Weekday) Period {
+	from := WeekStartOn(day, first)
+	return Period{From: from, To: from.AddDate(0, 0, 6)}
+}
+
+// MonthOf возвращает календарный месяц, в который входит day
+func MonthOf(day time.Time) Period {
+	from := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Period{From: from, To: from.AddDate(0, 1, -1)}
+}
+
+// LastDays возвращает последние days дней, включая today
+func LastDays(today time.Time, days int) Period {
+	return NewPeriod(today.AddDate(0, 0, -(days-1)), today)
+}
+
+// Days возвращает число дней в периоде
+func (p Period) Days() int {
+	return int(p.To.Sub(p.From).Hours()/24) + 1
+}
+
+// Contains проверяет, входит ли дата в период
+func (p Period) Contains(date time.Time) bool {
+	return !date.Before(p.From) && !date.After(p.To)
+}
+
+// PeriodStats статистика за период
+type PeriodStats struct {
+	UserID        int64
+	Period        Period
+	TotalCount    int
+	Approaches    int
+	TrainingDays  int
+	AveragePerDay float64 // среднее за день тренировки
+	BestDay       int
+	BestDayDate   time.Time
+	BestApproach  int
+}
+
+// NewPeriodStats считает статистику за период по сессиям. Сессии вне периода не учитываются.
+// Если начало периода не задано, период начинается с первой тренировки
+func NewPeriodStats(userID int64, period Period, sessions []*PushupSession) *PeriodStats {
+	stats := &PeriodStats{UserID: userID, Period: period}
+	if stats.Period.From.IsZero() {
+		stats.Period.From = stats.Period.To
+	}
+
+	daily := make(map[time.Time]int)
+	for _, session := range sessions {
+		if !period.Contains(session.Date) {
+			continue
+		}
+		if session.Date.Before(stats.Period.From) {
+			stats.Period.From = session.Date
+		}
+
+		daily[session.Date] += session.GetTotalCount()
+		stats.Approaches += session.GetApproachCount()
+		stats.BestApproach = max(stats.BestApproach, session.GetBestApproach())
+	}
+
+	for date, count := range daily {
+		if count == 0 {
+			continue
+		}
+		stats.TrainingDays++
+		stats.TotalCount += count
+		// При равенстве лучшим считается более ранний день, чтобы результат не зависел от порядка карты
+		if count > stats.BestDay || (count == stats.BestDay && date.Before(stats.BestDayDate)) {
+			stats.BestDay = count
+			stats.BestDayDate = date
+		}
+	}
+	if stats.TrainingDays > 0 {
+		stats.AveragePerDay = float64(stats.TotalCount) / float64(stats.TrainingDays)
+	}
+
+	return stats
+}
+
+// AveragePerPeriodDay возвращает среднее за день периода, включая дни без тренировок
+func (s *PeriodStats) AveragePerPeriodDay() float64 {
+	return float64(s.TotalCount) / float64(s.Period.Days())
+}
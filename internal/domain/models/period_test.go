@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeriodBounds(t *testing.T) {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		period Period
+		from   time.Time
+		to     time.Time
+		days   int
+	}{
+		{"один день", NewPeriod(date(time.October, 19), date(time.October, 19)), date(time.October, 19), date(time.October, 19), 1},
+		{"время дня отбрасывается", NewPeriod(date(time.October, 1).Add(15*time.Hour), date(time.October, 2).Add(23*time.Hour)), date(time.October, 1), date(time.October, 2), 2},
+		{"неделя с понедельника", WeekOf(date(time.October, 22), time.Monday), date(time.October, 19), date(time.October, 25), 7},
+		{"неделя с воскресенья", WeekOf(date(time.October, 22), time.Sunday), date(time.October, 18), date(time.October, 24), 7},
+		{"первый день недели", WeekOf(date(time.October, 18), time.Sunday), date(time.October, 18), date(time.October, 24), 7},
+		{"февраль", MonthOf(date(time.February, 14)), date(time.February, 1), date(time.February, 28), 28},
+		{"последние 7 дней", LastDays(date(time.October, 19), 7), date(time.October, 13), date(time.October, 19), 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.period.From.Equal(tt.from) || !tt.period.To.Equal(tt.to) || tt.period.Days() != tt.days {
+				t.Errorf("period = %v – %v (%d days), want %v – %v (%d days)",
+					tt.period.From, tt.period.To, tt.period.Days(), tt.from, tt.to, tt.days)
+			}
+			// Обе границы входят в период
+			if !tt.period.Contains(tt.from) || !tt.period.Contains(tt.to) {
+				t.Errorf("period %v – %v does not contain its bounds", tt.period.From, tt.period.To)
+			}
+			if tt.period.Contains(tt.from.AddDate(0, 0, -1)) || tt.period.Contains(tt.to.AddDate(0, 0, 1)) {
+				t.Errorf("period %v – %v contains days outside its bounds", tt.period.From, tt.period.To)
+			}
+		})
+	}
+}
+
+func TestNewPeriodStatsIncludesBounds(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2026, time.October, n, 0, 0, 0, 0, time.UTC)
+	}
+	session := func(n, count int) *PushupSession {
+		session := NewPushupSession(1, day(n))
+		session.Approaches = append(session.Approaches, PushupApproach{Count: count})
+		return session
+	}
+
+	sessions := []*PushupSession{session(9, 1), session(10, 10), session(12, 20), session(15, 30), session(16, 1)}
+	stats := NewPeriodStats(1, NewPeriod(day(10), day(15)), sessions)
+	if stats.TotalCount != 60 || stats.TrainingDays != 3 {
+		t.Errorf("stats = %d pushups in %d days, want 60 in 3 days", stats.TotalCount, stats.TrainingDays)
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserDataSummary данные пользователя, которые хранит бот, помимо профиля
+type UserDataSummary struct {
+	Account            bool // есть ли запись пользователя
+	Approaches         int
+	Pushups            int
+	FirstDay           time.Time // первый день с подходами, нулевое время если подходов нет
+	LastDay            time.Time
+	APITokens          int
+	PersonalRecords    int
+	ProgramEnrollments int
+	WeeklyDigests      int // отметки об отправленных сводках
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Program программа тренировок: недели из тренировочных дней с подходами на каждый день
+type Program struct {
+	ID          string
+	Title       string
+	Description string
+	// Scaled подходы заданы в процентах от максимума в одном подходе, который пользователь
+	// показал перед началом программы
+	Scaled bool
+	Weeks  []ProgramWeek
+}
+
+// ProgramWeek неделя программы
+type ProgramWeek struct {
+	Days []ProgramDay
+}
+
+// ProgramDay тренировочный день: количество в каждом подходе или проценты от максимума
+type ProgramDay struct {
+	Sets []int
+}
+
+// ProgramEvent изменение в программе после выполнения или пропуска дня
+type ProgramEvent int
+
+const (
+	ProgramEventNone          ProgramEvent = iota
+	ProgramEventDayCompleted               // день выполнен
+	ProgramEventWeekCompleted              // неделя выполнена без пропусков, начинается следующая
+	ProgramEventWeekRepeated               // в неделе был невыполненный день, она начинается заново
+	ProgramEventFinished                   // пройдена последняя неделя
+)
+
+// ProgramEnrollment участие пользователя в программе. Тренировочный день программы
+// выполняется в тот календарный день, когда пользователь начал его подходы. Если к концу
+// этого дня план не выполнен, день засчитывается как невыполненный, и программа переходит
+// к следующему. Неделя без невыполненных дней переводит программу на следующую неделю,
+// иначе неделя повторяется
+type ProgramEnrollment struct {
+	UserID          int64
+	ProgramID       string
+	InitialMax      int // максимум в одном подходе на старте, 0 для программ без масштабирования
+	Week            int // текущая неделя, с 0
+	Day             int // текущий день недели, с 0
+	Misses          int // невыполненные дни в текущей неделе
+	DayStartedOn    time.Time
+	LastCompletedOn time.Time
+	FinishedAt      *time.Time
+	EnrolledAt      time.Time
+}
+
+// NewProgramEnrollment создает участие в программе с первого дня
+func NewProgramEnrollment(userID int64, program *Program, initialMax int) *ProgramEnrollment {
+	if !program.Scaled {
+		initialMax = 0
+	}
+	return &ProgramEnrollment{
+		UserID:     userID,
+		ProgramID:  program.ID,
+		InitialMax: initialMax,
+		EnrolledAt: time.Now(),
+	}
+}
+
+// IsFinished проверяет, пройдена ли программа
+func (e *ProgramEnrollment) IsFinished() bool {
+	return e.FinishedAt != nil
+}
+
+// CompletedToday проверяет, выполнен ли план на день today
+func (e *ProgramEnrollment) CompletedToday(today time.Time) bool {
+	return !e.LastCompletedOn.IsZero() && e.LastCompletedOn.Equal(today)
+}
+
+// Prescription возвращает подходы текущего дня программы
+func (e *ProgramEnrollment) Prescription(program *Program) []int {
+	if e.IsFinished() || e.Week >= len(program.Weeks) || e.Day >= len(program.Weeks[e.Week].Days) {
+		return nil
+	}
+
+	sets := program.Weeks[e.Week].Days[e.Day].Sets
+	prescribed := make([]int, len(sets))
+	for i, set := range sets {
+		prescribed[i] = set
+		if program.Scaled {
+			prescribed[i] = max(1, int(math.Round(float64(e.InitialMax*set)/100)))
+		}
+	}
+	return prescribed
+}
+
+// Sync засчитывает невыполненный день, если он был начат раньше today. Возвращает событие
+// перехода, если программа сдвинулась
+func (e *ProgramEnrollment) Sync(program *Program, today time.Time) ProgramEvent {
+	if e.IsFinished() || e.DayStartedOn.IsZero() || !e.DayStartedOn.Before(today) {
+		return ProgramEventNone
+	}
+
+	e.Misses++
+	e.DayStartedOn = time.Time{}
+	return e.advance(program, today)
+}
+
+// Record учитывает, что за день today сделано dayTotal отжиманий. Если план дня выполнен,
+// программа переходит к следующему дню, а новые подходы в этот день уже не засчитываются
+func (e *ProgramEnrollment) Record(program *Program, today time.Time, dayTotal int) ProgramEvent {
+	event := e.Sync(program, today)
+	if e.IsFinished() || e.CompletedToday(today) {
+		return event
+	}
+
+	if e.DayStartedOn.IsZero() {
+		e.DayStartedOn = today
+	}
+
+	planned := 0
+	for _, set := range e.Prescription(program) {
+		planned += set
+	}
+	if dayTotal < planned {
+		return event
+	}
+
+	e.LastCompletedOn = today
+	e.DayStartedOn = time.Time{}
+	if next := e.advance(program, today); next != ProgramEventNone {
+		return next
+	}
+	// Повтор недели из-за вчерашнего пропуска важнее, чем выполненный день
+	if event != ProgramEventNone {
+		return event
+	}
+	return ProgramEventDayCompleted
+}
+
+// advance переходит к следующему дню программы
+func (e *ProgramEnrollment) advance(program *Program, today time.Time) ProgramEvent {
+	e.Day++
+	if e.Day < len(program.Weeks[e.Week].Days) {
+		return ProgramEventNone
+	}
+
+	e.Day = 0
+	if e.Misses > 0 {
+		e.Misses = 0
+		return ProgramEventWeekRepeated
+	}
+
+	e.Week++
+	if e.Week >= len(program.Weeks) {
+		finished := today
+		e.FinishedAt = &finished
+		return ProgramEventFinished
+	}
+	return ProgramEventWeekCompleted
+}
+
+// Remaining возвращает подходы, которые осталось сделать при dayTotal отжиманий за день:
+// сделанное вычитается из подходов по порядку, частично выполненный подход показывает остаток
+func Remaining(prescribed []int, dayTotal int) []int {
+	var remaining []int
+	for _, set := range prescribed {
+		switch {
+		case dayTotal >= set:
+			dayTotal -= set
+		case dayTotal > 0:
+			remaining = append(remaining, set-dayTotal)
+			dayTotal = 0
+		default:
+			remaining = append(remaining, set)
+		}
+	}
+	return remaining
+}
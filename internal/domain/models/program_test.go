@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// testProgram две недели по два дня
+var testProgram = &Program{
+	ID: "test",
+	Weeks: []ProgramWeek{
+		{Days: []ProgramDay{{Sets: []int{5, 5}}, {Sets: []int{6, 6}}}},
+		{Days: []ProgramDay{{Sets: []int{8, 8}}, {Sets: []int{10, 10}}}},
+	},
+}
+
+func TestProgramEnrollmentRecord(t *testing.T) {
+	day := func(n int) time.Time {
+		return time.Date(2026, time.March, 2+n, 0, 0, 0, 0, time.UTC)
+	}
+
+	type step struct {
+		day   int
+		total int
+		want  ProgramEvent
+	}
+	tests := []struct {
+		name     string
+		steps    []step
+		week     int
+		dayIndex int
+		finished bool
+	}{
+		{
+			name:  "план не выполнен",
+			steps: []step{{0, 9, ProgramEventNone}},
+		},
+		{
+			name:     "день выполнен",
+			steps:    []step{{0, 10, ProgramEventDayCompleted}},
+			dayIndex: 1,
+		},
+		{
+			name:     "повторные подходы в выполненный день не засчитываются",
+			steps:    []step{{0, 10, ProgramEventDayCompleted}, {0, 30, ProgramEventNone}},
+			dayIndex: 1,
+		},
+		{
+			name:  "неделя выполнена",
+			steps: []step{{0, 10, ProgramEventDayCompleted}, {1, 12, ProgramEventWeekCompleted}},
+			week:  1,
+		},
+		{
+			name: "неделя с пропуском повторяется",
+			steps: []step{
+				{0, 3, ProgramEventNone},
+				{1, 12, ProgramEventWeekRepeated},
+			},
+		},
+		{
+			name: "программа пройдена",
+			steps: []step{
+				{0, 10, ProgramEventDayCompleted},
+				{1, 12, ProgramEventWeekCompleted},
+				{2, 16, ProgramEventDayCompleted},
+				{3, 20, ProgramEventFinished},
+				{4, 100, ProgramEventNone},
+			},
+			week:     2,
+			finished: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enrollment := NewProgramEnrollment(1, testProgram, 0)
+			for i, s := range tt.steps {
+				if got := enrollment.Record(testProgram, day(s.day), s.total); got != s.want {
+					t.Fatalf("step %d: Record(day %d, %d) = %d, want %d", i, s.day, s.total, got, s.want)
+				}
+			}
+			if enrollment.Week != tt.week || enrollment.Day != tt.dayIndex || enrollment.IsFinished() != tt.finished {
+				t.Errorf("enrollment at week %d day %d finished %v, want week %d day %d finished %v",
+					enrollment.Week, enrollment.Day, enrollment.IsFinished(), tt.week, tt.dayIndex, tt.finished)
+			}
+		})
+	}
+}
+
+func TestProgramEnrollmentSync(t *testing.T) {
+	monday := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	enrollment := NewProgramEnrollment(1, testProgram, 0)
+	if got := enrollment.Sync(testProgram, monday); got != ProgramEventNone {
+		t.Fatalf("Sync before start = %d, want none", got)
+	}
+
+	enrollment.Record(testProgram, monday, 3)
+	if got := enrollment.Sync(testProgram, monday); got != ProgramEventNone || enrollment.Day != 0 {
+		t.Fatalf("Sync on the same day = %d at day %d, want none at day 0", got, enrollment.Day)
+	}
+	if got := enrollment.Sync(testProgram, monday.AddDate(0, 0, 1)); got != ProgramEventNone || enrollment.Day != 1 || enrollment.Misses != 1 {
+		t.Fatalf("Sync next day = %d at day %d with %d misses, want none at day 1 with 1 miss", got, enrollment.Day, enrollment.Misses)
+	}
+}
+
+func TestProgramEnrollmentPrescription(t *testing.T) {
+	scaled := &Program{ID: "scaled", Scaled: true, Weeks: []ProgramWeek{{Days: []ProgramDay{{Sets: []int{50, 75, 1}}}}}}
+
+	if got := fmt.Sprint(NewProgramEnrollment(1, scaled, 20).Prescription(scaled)); got != "[10 15 1]" {
+		t.Errorf("scaled Prescription = %s, want [10 15 1]", got)
+	}
+	if got := fmt.Sprint(NewProgramEnrollment(1, testProgram, 20).Prescription(testProgram)); got != "[5 5]" {
+		t.Errorf("Prescription = %s, want [5 5]", got)
+	}
+}
+
+func TestRemaining(t *testing.T) {
+	tests := []struct {
+		prescribed []int
+		dayTotal   int
+		want       []int
+	}{
+		{[]int{10, 8, 6}, 0, []int{10, 8, 6}},
+		{[]int{10, 8, 6}, 10, []int{8, 6}},
+		{[]int{10, 8, 6}, 13, []int{5, 6}},
+		{[]int{10, 8, 6}, 24, nil},
+		{[]int{10, 8, 6}, 50, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Remaining(tt.prescribed, tt.dayTotal); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("Remaining(%v, %d) = %v, want %v", tt.prescribed, tt.dayTotal, got, tt.want)
+		}
+	}
+}
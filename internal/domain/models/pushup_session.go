@@ -17,10 +17,10 @@ type PushupSession struct {
 
 // PushupApproach представляет один подход отжиманий
 type PushupApproach struct {
-	ID        int64
+	ID        int64 // 0 — подход еще не сохранен
 	SessionID int64
 	Count     int
-	CreatedAt time.Time
+	CreatedAt time.Time // нулевое для подходов, записанных до появления времени подходов
 }
 
 // NewPushupSession создает новую сессию отжиманий
@@ -53,6 +53,28 @@ func (ps *PushupSession) AddApproach(count int) error {
 	return nil
 }
 
+// AddApproaches добавляет несколько подходов сразу. Если хотя бы один не проходит
+// проверку, сессия не меняется, а ошибка указывает на этот подход
+func (ps *PushupSession) AddApproaches(counts ...int) error {
+	for i, count := range counts {
+		if err := validatePushupCount(count); err != nil {
+			return &errors.ApproachError{Index: i, Count: count, Err: err}
+		}
+	}
+
+	now := time.Now()
+	for _, count := range counts {
+		ps.Approaches = append(ps.Approaches, PushupApproach{
+			SessionID: ps.ID,
+			Count:     count,
+			CreatedAt: now,
+		})
+	}
+	ps.UpdatedAt = now
+
+	return nil
+}
+
 // GetTotalCount возвращает общее количество отжиманий в сессии
 func (ps *PushupSession) GetTotalCount() int {
 	total := 0
@@ -75,6 +97,28 @@ func (ps *PushupSession) GetAveragePerApproach() float64 {
 	return float64(ps.GetTotalCount()) / float64(len(ps.Approaches))
 }
 
+// GetBestApproach возвращает самый большой подход в сессии
+func (ps *PushupSession) GetBestApproach() int {
+	best := 0
+	for _, approach := range ps.Approaches {
+		best = max(best, approach.Count)
+	}
+	return best
+}
+
+// Gap возвращает перерыв перед подходом i. false, если это первый подход или время одного
+// из подходов неизвестно. Подходы из одного сообщения записаны одновременно, перерыв между ними 0
+func (ps *PushupSession) Gap(i int) (time.Duration, bool) {
+	if i <= 0 || i >= len(ps.Approaches) {
+		return 0, false
+	}
+	prev, cur := ps.Approaches[i-1].CreatedAt, ps.Approaches[i].CreatedAt
+	if prev.IsZero() || cur.IsZero() {
+		return 0, false
+	}
+	return cur.Sub(prev), true
+}
+
 // IsToday проверяет, является ли сессия за сегодня
 func (ps *PushupSession) IsToday() bool {
 	today := time.Now().Truncate(24 * time.Hour)
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// RecordKind вид личного рекорда
+type RecordKind string
+
+const (
+	RecordMaxSet        RecordKind = "max_set"        // больше всего отжиманий в одном подходе
+	RecordMaxDay        RecordKind = "max_day"        // больше всего отжиманий за день
+	RecordMaxWeek       RecordKind = "max_week"       // больше всего отжиманий за неделю
+	RecordLongestStreak RecordKind = "longest_streak" // самая длинная серия дней с тренировками подряд
+)
+
+// RecordKinds виды рекордов в порядке вывода
+var RecordKinds = []RecordKind{RecordMaxSet, RecordMaxDay, RecordMaxWeek, RecordLongestStreak}
+
+// PersonalRecord значение рекорда и день, когда он установлен
+type PersonalRecord struct {
+	Kind       RecordKind
+	Value      int
+	AchievedOn time.Time
+}
+
+// BrokenRecord рекорд, побитый последним сохранением
+type BrokenRecord struct {
+	PersonalRecord
+	Previous int // прежнее значение рекорда
+}
+
+// PersonalRecords рекорды пользователя и текущий прогресс, по которому они обновляются
+// без чтения всей истории
+type PersonalRecords struct {
+	UserID  int64
+	Records map[RecordKind]PersonalRecord
+
+	// Week сумма отжиманий за текущую неделю, AchievedOn — первый день этой недели
+	Week PersonalRecord
+	// Streak текущая серия дней, AchievedOn — последний день с тренировкой
+	Streak PersonalRecord
+}
+
+// NewPersonalRecords создает пустые рекорды пользователя
+func NewPersonalRecords(userID int64) *PersonalRecords {
+	return &PersonalRecords{
+		UserID:  userID,
+		Records: make(map[RecordKind]PersonalRecord),
+	}
+}
+
+// PersonalRecordsFromHistory вычисляет рекорды по сохраненным сессиям, недели начинаются
+// с дня first. Нужно один раз для пользователей, у которых история появилась раньше таблицы рекордов
+func PersonalRecordsFromHistory(userID int64, first time.Weekday, sessions []*PushupSession) *PersonalRecords {
+	records := NewPersonalRecords(userID)
+
+	sorted := append([]*PushupSession(nil), sessions...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	for _, session := range sorted {
+		if session.GetApproachCount() == 0 {
+			continue
+		}
+		counts := make([]int, 0, len(session.Approaches))
+		for _, approach := range session.Approaches {
+			counts = append(counts, approach.Count)
+		}
+		records.Apply(session.Date, first, counts, session.GetTotalCount())
+	}
+
+	return records
+}
+
+// Apply учитывает подходы counts, записанные в день day, где dayTotal — сумма за день вместе
+// с ними, а недели начинаются с дня first. Возвращает побитые рекорды. Рекорд дня, недели
+// или серии объявляется один раз, когда побит рекорд другого дня, недели или серии;
+// дальнейший рост обновляется молча. Первые значения не объявляются: побивать было нечего.
+// После смены первого дня недели сумма текущей недели считается заново
+func (r *PersonalRecords) Apply(day time.Time, first time.Weekday, counts []int, dayTotal int) []BrokenRecord {
+	day = day.Truncate(24 * time.Hour)
+	var broken []BrokenRecord
+
+	maxSet, sum := 0, 0
+	for _, count := range counts {
+		sum += count
+		maxSet = max(maxSet, count)
+	}
+
+	// Неделя
+	weekStart := WeekStartOn(day, first)
+	if !r.Week.AchievedOn.Equal(weekStart) {
+		r.Week = PersonalRecord{AchievedOn: weekStart}
+	}
+	r.Week.Value += sum
+
+	// Серия дней
+	switch {
+	case r.Streak.AchievedOn.Equal(day):
+	case r.Streak.Value > 0 && r.Streak.AchievedOn.Equal(day.AddDate(0, 0, -1)):
+		r.Streak = PersonalRecord{Value: r.Streak.Value + 1, AchievedOn: day}
+	default:
+		r.Streak = PersonalRecord{Value: 1, AchievedOn: day}
+	}
+
+	broken = r.update(broken, RecordMaxSet, maxSet, day, nil)
+	broken = r.update(broken, RecordMaxDay, dayTotal, day, sameDay)
+	broken = r.update(broken, RecordMaxWeek, r.Week.Value, day, sameWeek(first))
+	broken = r.update(broken, RecordLongestStreak, r.Streak.Value, day, sameStreak)
+
+	return broken
+}
+
+// samePeriod сообщает, что прежний рекорд, установленный в день a, относится к тому же
+// дню, неделе или серии, что и новое значение дня b
+type samePeriod func(a, b time.Time) bool
+
+func sameDay(a, b time.Time) bool {
+	return a.Truncate(24 * time.Hour).Equal(b.Truncate(24 * time.Hour))
+}
+
+// sameWeek сравнивает недели, которые начинаются с дня first
+func sameWeek(first time.Weekday) samePeriod {
+	return func(a, b time.Time) bool {
+		return WeekStartOn(a, first).Equal(WeekStartOn(b, first))
+	}
+}
+
+// sameStreak прежний рекорд серии установлен вчера или сегодня — значит, его продолжает
+// текущая серия
+func sameStreak(a, b time.Time) bool {
+	return sameDay(a, b) || sameDay(a, b.AddDate(0, 0, -1))
+}
+
+// update записывает новое значение рекорда, если оно больше прежнего
+func (r *PersonalRecords) update(broken []BrokenRecord, kind RecordKind, value int, day time.Time, same samePeriod) []BrokenRecord {
+	previous, ok := r.Records[kind]
+	if ok && value <= previous.Value {
+		return broken
+	}
+
+	record := PersonalRecord{Kind: kind, Value: value, AchievedOn: day}
+	r.Records[kind] = record
+
+	if !ok || previous.Value == 0 || (same != nil && same(previous.AchievedOn, day)) {
+		return broken
+	}
+	return append(broken, BrokenRecord{PersonalRecord: record, Previous: previous.Value})
+}
+
+// WeekStartOn возвращает первый день недели, в которую входит t, если неделя начинается с first
+func WeekStartOn(t time.Time, first time.Weekday) time.Time {
+	day := t.Truncate(24 * time.Hour)
+	offset := (int(day.Weekday()) - int(first) + 7) % 7
+	return day.AddDate(0, 0, -offset)
+}
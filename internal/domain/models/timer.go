@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// RestTimer напоминание о следующем подходе после отдыха
+type RestTimer struct {
+	UserID int64
+	DueAt  time.Time
+}
+
+// NewRestTimer создает напоминание через интервал отдыха пользователя
+func NewRestTimer(user *User, now time.Time) RestTimer {
+	return RestTimer{
+		UserID: user.ID,
+		DueAt:  now.Add(user.RestTimer),
+	}
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"azhumania/internal/domain/errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// База часовых поясов встроена в бинарник: в минимальных образах нет /usr/share/zoneinfo
+	_ "time/tzdata"
+)
+
+// ParseTimezone проверяет часовой пояс и возвращает имя для хранения. Смещение в целых часах
+// ("+3", "UTC-5", "GMT+10") превращается в пояс Etc/GMT, у которого знак обратный
+func ParseTimezone(timezone string) (string, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" {
+		return "", errors.ErrInvalidTimezone
+	}
+
+	offset := strings.TrimPrefix(strings.TrimPrefix(strings.ToUpper(timezone), "UTC"), "GMT")
+	if offset == "" {
+		return "UTC", nil
+	}
+	if offset[0] == '+' || offset[0] == '-' {
+		hours, err := strconv.Atoi(offset)
+		if err != nil || hours < -12 || hours > 14 {
+			return "", errors.ErrInvalidTimezone
+		}
+		if hours == 0 {
+			return "UTC", nil
+		}
+		return fmt.Sprintf("Etc/GMT%+d", -hours), nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil || location.String() == "Local" {
+		return "", errors.ErrInvalidTimezone
+	}
+	return location.String(), nil
+}
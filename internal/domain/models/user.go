@@ -5,14 +5,45 @@ import (
 	"time"
 )
 
+// Role роль пользователя
+type Role string
+
+const (
+	// RoleUser обычный пользователь
+	RoleUser Role = "user"
+	// RoleAdmin администратор бота, назначается списком admins в конфигурации
+	RoleAdmin Role = "admin"
+)
+
+// Границы таймера отдыха между подходами
+const (
+	DefaultRestTimer = 90 * time.Second
+	MinRestTimer     = 10 * time.Second
+	MaxRestTimer     = time.Hour
+)
+
+// MaxDailyGoal наибольшая дневная цель
+const MaxDailyGoal = 10000
+
+// DefaultWeekStart первый день недели по умолчанию, как в ISO 8601
+const DefaultWeekStart = time.Monday
+
 // User представляет пользователя в домене
 type User struct {
-	ID         int64
-	Phone      string
-	NickName   string
-	TelegramID int64
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID           int64
+	Phone        string
+	NickName     string
+	TelegramID   int64
+	Role         Role
+	BannedAt     *time.Time    // время блокировки, nil если пользователь не заблокирован
+	InactiveAt   *time.Time    // когда Telegram перестал доставлять сообщения (бот заблокирован, аккаунт удален), nil — доступен
+	RestTimer    time.Duration // отдых между подходами, после которого бот напоминает о следующем; 0 — выключено
+	WeeklyDigest bool          // подписка на сводку за неделю вечером в ее последний день
+	Timezone     string        // часовой пояс IANA, пусто — UTC
+	DailyGoal    int           // цель отжиманий на день, 0 — цель не задана
+	WeekStart    time.Weekday  // первый день недели в статистике
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 // NewUser создает нового пользователя
@@ -22,6 +53,8 @@ func NewUser(phone, nickname string, telegramID int64) *User {
 		Phone:      phone,
 		NickName:   nickname,
 		TelegramID: telegramID,
+		Role:       RoleUser,
+		WeekStart:  DefaultWeekStart,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}
@@ -46,3 +79,148 @@ func (u *User) UpdateNickname(nickname string) {
 	u.NickName = nickname
 	u.UpdatedAt = time.Now()
 }
+
+// IsAdmin проверяет, является ли пользователь администратором
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// IsBanned проверяет, заблокирован ли пользователь
+func (u *User) IsBanned() bool {
+	return u.BannedAt != nil
+}
+
+// IsInactive проверяет, перестал ли Telegram доставлять сообщения пользователю
+func (u *User) IsInactive() bool {
+	return u.InactiveAt != nil
+}
+
+// SetInactive отмечает, доставляются ли пользователю сообщения. Возвращает false, если отметка не изменилась
+func (u *User) SetInactive(inactive bool) bool {
+	if u.IsInactive() == inactive {
+		return false
+	}
+	now := time.Now()
+	u.InactiveAt = nil
+	if inactive {
+		u.InactiveAt = &now
+	}
+	u.UpdatedAt = now
+	return true
+}
+
+// SetRole меняет роль пользователя. Возвращает false, если роль не изменилась
+func (u *User) SetRole(role Role) bool {
+	if u.Role == role {
+		return false
+	}
+	u.Role = role
+	u.UpdatedAt = time.Now()
+	return true
+}
+
+// Ban блокирует пользователя. Администратора заблокировать нельзя
+func (u *User) Ban() error {
+	if u.IsAdmin() {
+		return errors.ErrCannotBanAdmin
+	}
+	if u.IsBanned() {
+		return nil
+	}
+	now := time.Now()
+	u.BannedAt = &now
+	u.UpdatedAt = now
+	return nil
+}
+
+// Unban снимает блокировку пользователя
+func (u *User) Unban() {
+	u.BannedAt = nil
+	u.UpdatedAt = time.Now()
+}
+
+// SetRestTimer меняет интервал таймера отдыха, 0 выключает таймер. Возвращает false,
+// если интервал не изменился
+func (u *User) SetRestTimer(interval time.Duration) (bool, error) {
+	if interval != 0 && (interval < MinRestTimer || interval > MaxRestTimer) {
+		return false, errors.ErrInvalidRestTimer
+	}
+	if u.RestTimer == interval {
+		return false, nil
+	}
+	u.RestTimer = interval
+	u.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// SetWeeklyDigest включает или выключает еженедельную сводку. Возвращает false, если настройка не изменилась
+func (u *User) SetWeeklyDigest(enabled bool) bool {
+	if u.WeeklyDigest == enabled {
+		return false
+	}
+	u.WeeklyDigest = enabled
+	u.UpdatedAt = time.Now()
+	return true
+}
+
+// SetTimezone меняет часовой пояс: имя IANA ("Europe/Moscow") или смещение от UTC
+// в часах ("+3", "UTC-5"). Возвращает false, если пояс не изменился
+func (u *User) SetTimezone(timezone string) (bool, error) {
+	name, err := ParseTimezone(timezone)
+	if err != nil {
+		return false, err
+	}
+	if u.Timezone == name {
+		return false, nil
+	}
+	u.Timezone = name
+	u.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// Location возвращает часовой пояс пользователя. Пояс, который не удалось загрузить, считается UTC
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// Today возвращает сегодняшний день по часовому поясу пользователя
+func (u *User) Today() time.Time {
+	return LocalDate(time.Now(), u.Location())
+}
+
+// LocalDate возвращает календарный день момента t по времени loc в том же виде, в каком
+// хранятся даты сессий: полночь UTC
+func LocalDate(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// SetDailyGoal меняет дневную цель, 0 убирает цель. Возвращает false, если цель не изменилась
+func (u *User) SetDailyGoal(goal int) (bool, error) {
+	if goal < 0 || goal > MaxDailyGoal {
+		return false, errors.ErrInvalidDailyGoal
+	}
+	if u.DailyGoal == goal {
+		return false, nil
+	}
+	u.DailyGoal = goal
+	u.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// SetWeekStart меняет первый день недели в статистике. Возвращает false, если он не изменился
+func (u *User) SetWeekStart(day time.Weekday) bool {
+	if u.WeekStart == day {
+		return false
+	}
+	u.WeekStart = day
+	u.UpdatedAt = time.Now()
+	return true
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/models"
+	"context"
+	"time"
+)
+
+// AdminRepository определяет интерфейс для административных запросов
+type AdminRepository interface {
+	// GetStats возвращает сводную статистику, активность считается начиная с since
+	GetStats(ctx context.Context, since time.Time) (*models.AdminStats, error)
+
+	// ListRecipients возвращает Telegram ID незаблокированных и доступных пользователей для рассылки
+	ListRecipients(ctx context.Context) ([]int64, error)
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// ChartRepository определяет интерфейс для временного хранения картинок графиков, которые
+// Telegram скачивает по ссылке
+type ChartRepository interface {
+	// Save сохраняет картинку на ttl и возвращает ее случайный ID
+	Save(ctx context.Context, data []byte, ttl time.Duration) (string, error)
+
+	// Get получает картинку по ID, nil если ее нет или срок хранения истек
+	Get(ctx context.Context, id string) ([]byte, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// DigestRepository определяет интерфейс для учета отправленных еженедельных сводок
+type DigestRepository interface {
+	// MarkSent отмечает сводку пользователя за неделю weekStart отправленной. Возвращает false,
+	// если она уже отмечена, в том числе другим экземпляром бота
+	MarkSent(ctx context.Context, userID int64, weekStart time.Time) (bool, error)
+}
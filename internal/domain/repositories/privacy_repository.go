@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/models"
+	"context"
+)
+
+// PrivacyRepository определяет интерфейс для просмотра и удаления всех данных пользователя
+type PrivacyRepository interface {
+	// Summary считает данные пользователя во всех хранилищах
+	Summary(ctx context.Context, userID int64) (*models.UserDataSummary, error)
+
+	// DeleteUser удаляет пользователя и все его данные, включая кэш, и записывает удаление
+	// в журнал. Возвращает, сколько данных удалено
+	DeleteUser(ctx context.Context, userID int64) (*models.UserDataSummary, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/models"
+	"context"
+)
+
+// ProgramRepository определяет интерфейс для работы с участием в программах тренировок
+type ProgramRepository interface {
+	// GetEnrollment получает участие пользователя в программе, nil если он не записан
+	GetEnrollment(ctx context.Context, userID int64) (*models.ProgramEnrollment, error)
+
+	// SaveEnrollment создает или обновляет участие в программе
+	SaveEnrollment(ctx context.Context, enrollment *models.ProgramEnrollment) error
+
+	// DeleteEnrollment удаляет участие в программе. Возвращает false, если пользователь не был записан
+	DeleteEnrollment(ctx context.Context, userID int64) (bool, error)
+}
@@ -8,18 +8,15 @@ import (
 
 // PushupRepository определяет интерфейс для работы с отжиманиями
 type PushupRepository interface {
-	// GetTodaySession получает сессию отжиманий за сегодня
-	GetTodaySession(ctx context.Context, userID int64) (*models.PushupSession, error)
+	// GetTodaySession получает сессию отжиманий за сегодняшний день пользователя today
+	GetTodaySession(ctx context.Context, userID int64, today time.Time) (*models.PushupSession, error)
 
-	// SaveSession сохраняет сессию отжиманий
+	// GetSession получает сессию отжиманий за день date, nil если подходов не было
+	GetSession(ctx context.Context, userID int64, date time.Time) (*models.PushupSession, error)
+
+	// SaveSession сохраняет новые подходы сессии (с нулевым ID) и проставляет им ID
 	SaveSession(ctx context.Context, session *models.PushupSession) error
 
-	// GetSessionsByDateRange получает сессии в диапазоне дат
+	// GetSessionsByDateRange получает сессии за дни с from по to включительно
 	GetSessionsByDateRange(ctx context.Context, userID int64, from, to time.Time) ([]*models.PushupSession, error)
-
-	// GetWeeklyStats получает статистику за неделю
-	GetWeeklyStats(ctx context.Context, userID int64) (*models.WeeklyStats, error)
-
-	// GetMonthlyStats получает статистику за месяц
-	GetMonthlyStats(ctx context.Context, userID int64) (*models.MonthlyStats, error)
 }
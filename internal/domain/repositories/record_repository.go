@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/models"
+	"context"
+)
+
+// RecordRepository определяет интерфейс для работы с личными рекордами
+type RecordRepository interface {
+	// GetRecords получает рекорды пользователя, nil если они еще не сохранялись
+	GetRecords(ctx context.Context, userID int64) (*models.PersonalRecords, error)
+
+	// SaveRecords сохраняет рекорды и текущий прогресс пользователя
+	SaveRecords(ctx context.Context, records *models.PersonalRecords) error
+}
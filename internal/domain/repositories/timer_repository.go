@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/models"
+	"context"
+	"time"
+)
+
+// TimerRepository определяет интерфейс для хранения таймеров отдыха. У пользователя не больше
+// одного таймера: новый заменяет предыдущий
+type TimerRepository interface {
+	// Schedule ставит таймер пользователя, заменяя предыдущий
+	Schedule(ctx context.Context, timer models.RestTimer) error
+
+	// Cancel снимает таймер пользователя, если он есть
+	Cancel(ctx context.Context, userID int64) error
+
+	// ClaimDue забирает не больше limit таймеров, сработавших к now. Забранный таймер удаляется
+	// и не достанется другому экземпляру бота
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]models.RestTimer, error)
+}
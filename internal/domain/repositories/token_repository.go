@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+)
+
+// TokenRepository определяет интерфейс для работы с токенами API
+type TokenRepository interface {
+	// Replace заменяет все токены пользователя новым
+	Replace(ctx context.Context, userID int64, tokenHash string) error
+
+	// GetUserID возвращает ID пользователя, которому выдан токен
+	GetUserID(ctx context.Context, tokenHash string) (int64, error)
+
+	// DeleteByUser удаляет все токены пользователя
+	DeleteByUser(ctx context.Context, userID int64) error
+}
@@ -21,4 +21,7 @@ type UserRepository interface {
 
 	// Exists проверяет существование пользователя
 	Exists(ctx context.Context, telegramID int64) (bool, error)
+
+	// ListDigestSubscribers получает незаблокированных и доступных пользователей, подписанных на еженедельную сводку
+	ListDigestSubscribers(ctx context.Context) ([]*models.User, error)
 }
@@ -0,0 +1,24 @@
+package ratelimit
+
+import (
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/repository/cache/redis"
+	"context"
+)
+
+// RedisLimiter хранит корзины в Redis, чтобы лимиты были общими для нескольких экземпляров бота
+type RedisLimiter struct {
+	cache redis.IRateLimitCache
+}
+
+// NewRedisLimiter создает новый ограничитель поверх Redis
+func NewRedisLimiter(cache redis.IRateLimitCache) ratelimit.Limiter {
+	return &RedisLimiter{
+		cache: cache,
+	}
+}
+
+// Allow забирает один токен из корзины key
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit) (bool, error) {
+	return l.cache.AllowRate(ctx, key, limit.Every, limit.Burst)
+}
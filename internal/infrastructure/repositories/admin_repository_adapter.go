@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	domainModels "azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/database/psql"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// AdminRepositoryAdapter адаптирует базу данных к доменному интерфейсу административных запросов.
+// Запросы идут мимо кэша: данные нужны актуальные и запрашиваются редко
+type AdminRepositoryAdapter struct {
+	db     psql.IDatabase
+	logger *zerolog.Logger
+}
+
+// NewAdminRepositoryAdapter создает новый адаптер административного репозитория
+func NewAdminRepositoryAdapter(db psql.IDatabase, logger *zerolog.Logger) repositories.AdminRepository {
+	return &AdminRepositoryAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetStats возвращает сводную статистику бота
+func (r *AdminRepositoryAdapter) GetStats(ctx context.Context, since time.Time) (*domainModels.AdminStats, error) {
+	stats, err := r.db.GetAdminStats(ctx, since)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("failed to get admin stats from database")
+		return nil, err
+	}
+
+	return &domainModels.AdminStats{
+		Users:           stats.Users,
+		BannedUsers:     stats.BannedUsers,
+		InactiveUsers:   stats.InactiveUsers,
+		ActiveToday:     stats.ActiveToday,
+		ApproachesToday: stats.ApproachesToday,
+		PushupsToday:    stats.PushupsToday,
+	}, nil
+}
+
+// ListRecipients возвращает ID незаблокированных и доступных пользователей. ID в БД совпадает с Telegram ID
+func (r *AdminRepositoryAdapter) ListRecipients(ctx context.Context) ([]int64, error) {
+	ids, err := r.db.ListActiveUserIDs(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("failed to list broadcast recipients")
+		return nil, err
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// backgroundTimeout ограничивает время одной фоновой операции
+const backgroundTimeout = 5 * time.Second
+
+// Background запускает фоновые операции адаптеров (запись в кэш) и позволяет дождаться
+// их завершения при остановке приложения
+type Background struct {
+	wg sync.WaitGroup
+}
+
+// NewBackground создает новый трекер фоновых операций
+func NewBackground() *Background {
+	return &Background{}
+}
+
+// Go запускает fn в отдельной горутине. Контекст операции наследует значения ctx,
+// но не его отмену, чтобы запись не обрывалась вместе с обработкой обновления
+func (b *Background) Go(ctx context.Context, fn func(ctx context.Context)) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundTimeout)
+		defer cancel()
+
+		fn(ctx)
+	}()
+}
+
+// Wait дожидается завершения всех фоновых операций или отмены ctx
+func (b *Background) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
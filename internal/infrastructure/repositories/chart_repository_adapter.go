@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/cache/redis"
+	"context"
+	"crypto/rand"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ChartRepositoryAdapter хранит картинки графиков в Redis, чтобы ссылка работала на любом экземпляре бота
+type ChartRepositoryAdapter struct {
+	cache  redis.ICache
+	logger *zerolog.Logger
+}
+
+// NewChartRepositoryAdapter создает новый адаптер репозитория графиков
+func NewChartRepositoryAdapter(cache redis.ICache, logger *zerolog.Logger) repositories.ChartRepository {
+	return &ChartRepositoryAdapter{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// Save сохраняет картинку под случайным ID: ссылку нельзя угадать, поэтому она не раскрывает
+// чужую статистику
+func (r *ChartRepositoryAdapter) Save(ctx context.Context, data []byte, ttl time.Duration) (string, error) {
+	id := rand.Text()
+	if err := r.cache.SetChart(ctx, id, data, ttl); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("failed to save chart to cache")
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Get получает картинку по ID
+func (r *ChartRepositoryAdapter) Get(ctx context.Context, id string) ([]byte, error) {
+	data, err := r.cache.GetChart(ctx, id)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Str("chartID", id).Msg("failed to get chart from cache")
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/database/psql"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DigestRepositoryAdapter адаптирует базу данных к доменному интерфейсу еженедельных сводок
+type DigestRepositoryAdapter struct {
+	db     psql.IDatabase
+	logger *zerolog.Logger
+}
+
+// NewDigestRepositoryAdapter создает новый адаптер репозитория сводок
+func NewDigestRepositoryAdapter(db psql.IDatabase, logger *zerolog.Logger) repositories.DigestRepository {
+	return &DigestRepositoryAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// MarkSent отмечает сводку за неделю отправленной
+func (r *DigestRepositoryAdapter) MarkSent(ctx context.Context, userID int64, weekStart time.Time) (bool, error) {
+	marked, err := r.db.MarkWeeklyDigestSent(ctx, userID, weekStart)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Time("weekStart", weekStart).Msg("failed to mark weekly digest sent in database")
+		return false, err
+	}
+
+	return marked, nil
+}
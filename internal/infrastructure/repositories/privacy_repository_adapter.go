@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	domainModels "azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/cache/redis"
+	"azhumania/internal/repository/database/psql"
+	repoModels "azhumania/internal/repository/models"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// PrivacyRepositoryAdapter адаптирует базу данных и кэш к доменному интерфейсу данных пользователя
+type PrivacyRepositoryAdapter struct {
+	db     psql.IDatabase
+	cache  redis.ICache
+	bg     *Background
+	logger *zerolog.Logger
+}
+
+// NewPrivacyRepositoryAdapter создает новый адаптер репозитория данных пользователя
+func NewPrivacyRepositoryAdapter(db psql.IDatabase, cache redis.ICache, bg *Background, logger *zerolog.Logger) repositories.PrivacyRepository {
+	return &PrivacyRepositoryAdapter{
+		db:     db,
+		cache:  cache,
+		bg:     bg,
+		logger: logger,
+	}
+}
+
+// Summary считает данные пользователя в базе данных. Кэш содержит только копии этих данных
+func (r *PrivacyRepositoryAdapter) Summary(ctx context.Context, userID int64) (*domainModels.UserDataSummary, error) {
+	summary, err := r.db.GetUserDataSummary(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get user data summary from database")
+		return nil, err
+	}
+
+	return r.convertToDomainSummary(summary), nil
+}
+
+// DeleteUser удаляет данные пользователя из базы данных, затем из кэша
+func (r *PrivacyRepositoryAdapter) DeleteUser(ctx context.Context, userID int64) (*domainModels.UserDataSummary, error) {
+	deleted, err := r.db.DeleteUserData(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to delete user data from database")
+		return nil, err
+	}
+
+	// Фоновые записи в кэш, начатые до удаления (например, пользователь, загруженный этим же
+	// сообщением), вернули бы данные в кэш после очистки. Ждем их не дольше, чем они могут идти
+	waitCtx, cancel := context.WithTimeout(ctx, backgroundTimeout)
+	defer cancel()
+	if err := r.bg.Wait(waitCtx); err != nil {
+		logging.FromContext(ctx, r.logger).Warn().Err(err).Int64("userID", userID).Msg("background cache writes still running before cache cleanup")
+	}
+
+	// Без очистки кэша бот продолжал бы видеть удаленного пользователя, поэтому ошибка
+	// возвращается: повторное удаление найдет пустую базу и снова очистит кэш
+	if err := r.cache.DeleteUserData(ctx, userID); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to delete user data from cache")
+		return nil, err
+	}
+
+	return r.convertToDomainSummary(deleted), nil
+}
+
+// convertToDomainSummary конвертирует счетчики из БД в доменную модель
+func (r *PrivacyRepositoryAdapter) convertToDomainSummary(summary repoModels.UserDataSummary) *domainModels.UserDataSummary {
+	return &domainModels.UserDataSummary{
+		Account:            summary.Users > 0,
+		Approaches:         summary.Approaches,
+		Pushups:            summary.Pushups,
+		FirstDay:           fromNullDate(summary.FirstDate),
+		LastDay:            fromNullDate(summary.LastDate),
+		APITokens:          summary.APITokens,
+		PersonalRecords:    summary.PersonalRecords,
+		ProgramEnrollments: summary.ProgramEnrollments,
+		WeeklyDigests:      summary.WeeklyDigests,
+	}
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	domainModels "azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/database/psql"
+	repoModels "azhumania/internal/repository/models"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ProgramRepositoryAdapter адаптирует базу данных к доменному интерфейсу программ тренировок
+type ProgramRepositoryAdapter struct {
+	db     psql.IDatabase
+	logger *zerolog.Logger
+}
+
+// NewProgramRepositoryAdapter создает новый адаптер репозитория программ
+func NewProgramRepositoryAdapter(db psql.IDatabase, logger *zerolog.Logger) repositories.ProgramRepository {
+	return &ProgramRepositoryAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetEnrollment получает участие пользователя в программе
+func (r *ProgramRepositoryAdapter) GetEnrollment(ctx context.Context, userID int64) (*domainModels.ProgramEnrollment, error) {
+	enrollment, err := r.db.GetProgramEnrollment(ctx, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get program enrollment from database")
+		return nil, err
+	}
+
+	return &domainModels.ProgramEnrollment{
+		UserID:          enrollment.UserID,
+		ProgramID:       enrollment.ProgramID,
+		InitialMax:      enrollment.InitialMax,
+		Week:            enrollment.Week,
+		Day:             enrollment.Day,
+		Misses:          enrollment.Misses,
+		DayStartedOn:    fromNullDate(enrollment.DayStartedOn),
+		LastCompletedOn: fromNullDate(enrollment.LastCompletedOn),
+		FinishedAt:      enrollment.FinishedAt,
+		EnrolledAt:      enrollment.EnrolledAt,
+	}, nil
+}
+
+// SaveEnrollment сохраняет участие в программе
+func (r *ProgramRepositoryAdapter) SaveEnrollment(ctx context.Context, enrollment *domainModels.ProgramEnrollment) error {
+	err := r.db.SaveProgramEnrollment(ctx, repoModels.ProgramEnrollment{
+		UserID:          enrollment.UserID,
+		ProgramID:       enrollment.ProgramID,
+		InitialMax:      enrollment.InitialMax,
+		Week:            enrollment.Week,
+		Day:             enrollment.Day,
+		Misses:          enrollment.Misses,
+		DayStartedOn:    toNullDate(enrollment.DayStartedOn),
+		LastCompletedOn: toNullDate(enrollment.LastCompletedOn),
+		FinishedAt:      enrollment.FinishedAt,
+		EnrolledAt:      enrollment.EnrolledAt,
+	})
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", enrollment.UserID).Msg("failed to save program enrollment to database")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteEnrollment удаляет участие в программе
+func (r *ProgramRepositoryAdapter) DeleteEnrollment(ctx context.Context, userID int64) (bool, error) {
+	deleted, err := r.db.DeleteProgramEnrollment(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to delete program enrollment from database")
+		return false, err
+	}
+
+	return deleted, nil
+}
+
+// fromNullDate конвертирует дату из БД, где NULL означает "не было", в нулевое время домена
+func fromNullDate(date *time.Time) time.Time {
+	if date == nil {
+		return time.Time{}
+	}
+	return *date
+}
+
+// toNullDate конвертирует нулевое время домена в NULL
+func toNullDate(date time.Time) *time.Time {
+	if date.IsZero() {
+		return nil
+	}
+	return &date
+}
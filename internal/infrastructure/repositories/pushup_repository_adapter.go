@@ -3,6 +3,7 @@ package repositories
 import (
 	domainModels "azhumania/internal/domain/models"
 	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
 	"azhumania/internal/repository/cache/redis"
 	"azhumania/internal/repository/database/psql"
 	repoModels "azhumania/internal/repository/models"
@@ -17,89 +18,113 @@ import (
 type PushupRepositoryAdapter struct {
 	db     psql.IDatabase
 	cache  redis.ICache
+	bg     *Background
 	logger *zerolog.Logger
 }
 
 // NewPushupRepositoryAdapter создает новый адаптер репозитория отжиманий
-func NewPushupRepositoryAdapter(db psql.IDatabase, cache redis.ICache, logger *zerolog.Logger) repositories.PushupRepository {
+func NewPushupRepositoryAdapter(db psql.IDatabase, cache redis.ICache, bg *Background, logger *zerolog.Logger) repositories.PushupRepository {
 	return &PushupRepositoryAdapter{
 		db:     db,
 		cache:  cache,
+		bg:     bg,
 		logger: logger,
 	}
 }
 
-// GetTodaySession получает сессию отжиманий за сегодня
-func (r *PushupRepositoryAdapter) GetTodaySession(ctx context.Context, userID int64) (*domainModels.PushupSession, error) {
-	today := time.Now().Truncate(24 * time.Hour)
-
+// GetTodaySession получает сессию отжиманий за сегодняшний день пользователя today
+func (r *PushupRepositoryAdapter) GetTodaySession(ctx context.Context, userID int64, today time.Time) (*domainModels.PushupSession, error) {
 	// Сначала пробуем из кэша
 	repoAzhumaniaList, err := r.cache.GetAzhumania(ctx, userID)
 	if err == nil && len(repoAzhumaniaList) > 0 {
-		// Ищем запись за сегодня
-		for _, azhumania := range repoAzhumaniaList {
-			if azhumania.Date.Equal(today) {
-				return r.convertToDomainSession(azhumania), nil
-			}
+		if approaches := approachesOn(repoAzhumaniaList, today); len(approaches) > 0 {
+			return r.convertToDomainSession(userID, today, approaches), nil
 		}
 	}
 
 	// Если нет в кэше, получаем из БД
-	repoAzhumaniaList, err = r.db.GetAzhumania(ctx, userID)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil // Нет данных за сегодня
+	approaches, err := r.getApproaches(ctx, userID, today)
+	if err != nil || len(approaches) == 0 {
+		return nil, err
+	}
+
+	// Сохраняем в кэш асинхронно
+	r.bg.Go(ctx, func(ctx context.Context) {
+		if err := r.cache.SetAzhumania(ctx, approaches...); err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to cache azhumania")
 		}
-		r.logger.Error().Err(err).Int64("userID", userID).Msg("failed to get azhumania from database")
+	})
+
+	return r.convertToDomainSession(userID, today, approaches), nil
+}
+
+// GetSession получает сессию отжиманий за день date. Кэш хранит только подходы, записанные
+// после его создания, поэтому прошлые дни читаются из БД
+func (r *PushupRepositoryAdapter) GetSession(ctx context.Context, userID int64, date time.Time) (*domainModels.PushupSession, error) {
+	approaches, err := r.getApproaches(ctx, userID, date)
+	if err != nil || len(approaches) == 0 {
 		return nil, err
 	}
 
-	// Ищем запись за сегодня
-	for _, azhumania := range repoAzhumaniaList {
-		if azhumania.Date.Equal(today) {
-			// Сохраняем в кэш асинхронно
-			go func() {
-				ctx := context.Background()
-				if err := r.cache.SetAzhumania(ctx, azhumania); err != nil {
-					r.logger.Error().Err(err).Int64("userID", userID).Msg("failed to cache azhumania")
-				}
-			}()
-
-			return r.convertToDomainSession(azhumania), nil
+	return r.convertToDomainSession(userID, date, approaches), nil
+}
+
+// getApproaches получает из БД подходы пользователя за день date
+func (r *PushupRepositoryAdapter) getApproaches(ctx context.Context, userID int64, date time.Time) ([]repoModels.Azhumania, error) {
+	repoAzhumaniaList, err := r.db.GetAzhumania(ctx, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
 		}
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get azhumania from database")
+		return nil, err
 	}
 
-	return nil, nil // Нет данных за сегодня
+	return approachesOn(repoAzhumaniaList, date), nil
 }
 
-// SaveSession сохраняет сессию отжиманий
+// SaveSession сохраняет новые подходы сессии: по строке на подход
 func (r *PushupRepositoryAdapter) SaveSession(ctx context.Context, session *domainModels.PushupSession) error {
-	repoAzhumania := r.convertToRepoAzhumania(session)
+	var added []int
+	var repoAzhumania []repoModels.Azhumania
+	for i, approach := range session.Approaches {
+		if approach.ID != 0 {
+			continue
+		}
+		added = append(added, i)
+		repoAzhumania = append(repoAzhumania, r.convertToRepoAzhumania(session, approach))
+	}
+	if len(repoAzhumania) == 0 {
+		return nil
+	}
 
 	// Сохраняем в БД
-	err := r.db.AddAzhumania(ctx, repoAzhumania)
+	ids, err := r.db.AddAzhumania(ctx, repoAzhumania)
 	if err != nil {
-		r.logger.Error().Err(err).Int64("userID", session.UserID).Msg("failed to save azhumania to database")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", session.UserID).Msg("failed to save azhumania to database")
 		return err
 	}
+	for j, i := range added {
+		session.Approaches[i].ID = ids[j]
+		repoAzhumania[j].ID = ids[j]
+	}
 
 	// Сохраняем в кэш асинхронно
-	go func() {
-		ctx := context.Background()
-		if err := r.cache.SetAzhumania(ctx, repoAzhumania); err != nil {
-			r.logger.Error().Err(err).Int64("userID", session.UserID).Msg("failed to cache azhumania")
+	r.bg.Go(ctx, func(ctx context.Context) {
+		if err := r.cache.SetAzhumania(ctx, repoAzhumania...); err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", session.UserID).Msg("failed to cache azhumania")
 		}
-	}()
+	})
 
 	return nil
 }
 
-// GetSessionsByDateRange получает сессии в диапазоне дат
+// GetSessionsByDateRange получает сессии за дни с from по to включительно
 func (r *PushupRepositoryAdapter) GetSessionsByDateRange(ctx context.Context, userID int64, from, to time.Time) ([]*domainModels.PushupSession, error) {
 	// Получаем все записи пользователя
 	repoAzhumaniaList, err := r.db.GetAzhumania(ctx, userID)
 	if err != nil {
-		r.logger.Error().Err(err).Int64("userID", userID).Msg("failed to get azhumania for date range")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get azhumania for date range")
 		return nil, err
 	}
 
@@ -110,8 +135,8 @@ func (r *PushupRepositoryAdapter) GetSessionsByDateRange(ctx context.Context, us
 
 	// Обрабатываем записи в диапазоне дат
 	for _, azhumania := range repoAzhumaniaList {
-		// Проверяем, что запись входит в диапазон
-		if azhumania.Date.After(from) && azhumania.Date.Before(to) {
+		// Проверяем, что запись входит в диапазон: границы — тоже дни периода
+		if !azhumania.Date.Before(from.Truncate(24*time.Hour)) && !azhumania.Date.After(to) {
 			dayKey := azhumania.Date.Truncate(24 * time.Hour)
 
 			// Создаем или получаем сессию для этого дня
@@ -123,12 +148,7 @@ func (r *PushupRepositoryAdapter) GetSessionsByDateRange(ctx context.Context, us
 			}
 
 			// Добавляем подход
-			approach := domainModels.PushupApproach{
-				SessionID: session.ID,
-				Count:     azhumania.Count,
-				CreatedAt: azhumania.Date,
-			}
-			session.Approaches = append(session.Approaches, approach)
+			session.Approaches = append(session.Approaches, r.convertToDomainApproach(session.ID, azhumania))
 		}
 	}
 
@@ -140,91 +160,53 @@ func (r *PushupRepositoryAdapter) GetSessionsByDateRange(ctx context.Context, us
 	return sessions, nil
 }
 
-// GetWeeklyStats получает статистику за неделю
-func (r *PushupRepositoryAdapter) GetWeeklyStats(ctx context.Context, userID int64) (*domainModels.WeeklyStats, error) {
-	now := time.Now()
-	weekStart := now.AddDate(0, 0, -int(now.Weekday()))
-	weekEnd := weekStart.AddDate(0, 0, 7)
-
-	// Получаем все записи пользователя
-	repoAzhumaniaList, err := r.db.GetAzhumania(ctx, userID)
-	if err != nil {
-		r.logger.Error().Err(err).Int64("userID", userID).Msg("failed to get azhumania for weekly stats")
-		return nil, err
-	}
-
-	stats := domainModels.NewWeeklyStats(userID, weekStart)
-
-	// Создаем карту для подсчета отжиманий по дням
-	dailyCounts := make(map[time.Time]int)
+// convertToDomainSession собирает доменную сессию из подходов за день
+func (r *PushupRepositoryAdapter) convertToDomainSession(userID int64, date time.Time, approaches []repoModels.Azhumania) *domainModels.PushupSession {
+	session := domainModels.NewPushupSession(userID, date)
+	session.ID = userID // Используем UserID как ID сессии
 
-	// Обрабатываем записи за неделю
-	for _, azhumania := range repoAzhumaniaList {
-		// Проверяем, что запись входит в неделю
-		if azhumania.Date.After(weekStart) && azhumania.Date.Before(weekEnd) {
-			dayKey := azhumania.Date.Truncate(24 * time.Hour)
-			dailyCounts[dayKey] += azhumania.Count
-		}
+	for _, approach := range approaches {
+		session.Approaches = append(session.Approaches, r.convertToDomainApproach(session.ID, approach))
 	}
-
-	// Вычисляем статистику
-	stats.TrainingDays = len(dailyCounts)
-	stats.TotalCount = 0
-	stats.BestDay = 0
-
-	for _, count := range dailyCounts {
-		stats.TotalCount += count
-		if count > stats.BestDay {
-			stats.BestDay = count
-		}
+	if last := session.Approaches[len(session.Approaches)-1].CreatedAt; !last.IsZero() {
+		session.UpdatedAt = last
 	}
 
-	if stats.TrainingDays > 0 {
-		stats.AveragePerDay = float64(stats.TotalCount) / float64(stats.TrainingDays)
-	}
-
-	return stats, nil
-}
-
-// GetMonthlyStats получает статистику за месяц
-func (r *PushupRepositoryAdapter) GetMonthlyStats(ctx context.Context, userID int64) (*domainModels.MonthlyStats, error) {
-	// TODO: Реализовать получение статистики за месяц
-	// Пока возвращаем заглушку
-	now := time.Now()
-	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-
-	stats := domainModels.NewMonthlyStats(userID, monthStart)
-	stats.TotalCount = 0
-	stats.TrainingDays = 0
-	stats.AveragePerDay = 0
-	stats.BestDay = 0
-	stats.Streak = 0
-
-	return stats, nil
+	return session
 }
 
-// convertToDomainSession конвертирует репозиторную модель в доменную сессию
-func (r *PushupRepositoryAdapter) convertToDomainSession(repoAzhumania repoModels.Azhumania) *domainModels.PushupSession {
-	session := domainModels.NewPushupSession(repoAzhumania.UserID, repoAzhumania.Date)
-	session.ID = repoAzhumania.UserID // Используем UserID как ID сессии
-
-	// Создаем один подход с общим количеством отжиманий
-	// TODO: Изменить структуру БД для хранения отдельных подходов
+// convertToDomainApproach конвертирует строку таблицы в доменный подход
+func (r *PushupRepositoryAdapter) convertToDomainApproach(sessionID int64, repoAzhumania repoModels.Azhumania) domainModels.PushupApproach {
 	approach := domainModels.PushupApproach{
-		SessionID: session.ID,
+		ID:        repoAzhumania.ID,
+		SessionID: sessionID,
 		Count:     repoAzhumania.Count,
-		CreatedAt: repoAzhumania.Date,
 	}
-	session.Approaches = append(session.Approaches, approach)
+	if repoAzhumania.CreatedAt != nil {
+		approach.CreatedAt = *repoAzhumania.CreatedAt
+	}
 
-	return session
+	return approach
 }
 
-// convertToRepoAzhumania конвертирует доменную сессию в репозиторную модель
-func (r *PushupRepositoryAdapter) convertToRepoAzhumania(session *domainModels.PushupSession) repoModels.Azhumania {
+// convertToRepoAzhumania конвертирует доменный подход в строку таблицы
+func (r *PushupRepositoryAdapter) convertToRepoAzhumania(session *domainModels.PushupSession, approach domainModels.PushupApproach) repoModels.Azhumania {
+	createdAt := approach.CreatedAt
 	return repoModels.Azhumania{
-		UserID: session.UserID,
-		Date:   session.Date,
-		Count:  session.GetTotalCount(),
+		UserID:    session.UserID,
+		Date:      session.Date,
+		Count:     approach.Count,
+		CreatedAt: &createdAt,
+	}
+}
+
+// approachesOn выбирает подходы за день date в порядке записи
+func approachesOn(repoAzhumaniaList []repoModels.Azhumania, date time.Time) []repoModels.Azhumania {
+	var approaches []repoModels.Azhumania
+	for _, azhumania := range repoAzhumaniaList {
+		if azhumania.Date.Equal(date) {
+			approaches = append(approaches, azhumania)
+		}
 	}
+	return approaches
 }
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	domainModels "azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/database/psql"
+	repoModels "azhumania/internal/repository/models"
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// Строки текущего прогресса в таблице рекордов
+const (
+	progressWeek   = "current_week"
+	progressStreak = "current_streak"
+)
+
+// RecordRepositoryAdapter адаптирует базу данных к доменному интерфейсу личных рекордов.
+// Рекорды читаются по первичному ключу, поэтому кэш не нужен
+type RecordRepositoryAdapter struct {
+	db     psql.IDatabase
+	logger *zerolog.Logger
+}
+
+// NewRecordRepositoryAdapter создает новый адаптер репозитория рекордов
+func NewRecordRepositoryAdapter(db psql.IDatabase, logger *zerolog.Logger) repositories.RecordRepository {
+	return &RecordRepositoryAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetRecords получает рекорды пользователя
+func (r *RecordRepositoryAdapter) GetRecords(ctx context.Context, userID int64) (*domainModels.PersonalRecords, error) {
+	rows, err := r.db.GetPersonalRecords(ctx, userID)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to get personal records from database")
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	records := domainModels.NewPersonalRecords(userID)
+	for _, row := range rows {
+		record := domainModels.PersonalRecord{
+			Kind:       domainModels.RecordKind(row.Kind),
+			Value:      row.Value,
+			AchievedOn: row.AchievedOn,
+		}
+
+		switch row.Kind {
+		case progressWeek:
+			records.Week = record
+		case progressStreak:
+			records.Streak = record
+		default:
+			records.Records[record.Kind] = record
+		}
+	}
+
+	return records, nil
+}
+
+// SaveRecords сохраняет рекорды и текущий прогресс пользователя
+func (r *RecordRepositoryAdapter) SaveRecords(ctx context.Context, records *domainModels.PersonalRecords) error {
+	rows := []repoModels.PersonalRecord{
+		r.convertToRepoRecord(records.UserID, progressWeek, records.Week),
+		r.convertToRepoRecord(records.UserID, progressStreak, records.Streak),
+	}
+	for kind, record := range records.Records {
+		rows = append(rows, r.convertToRepoRecord(records.UserID, string(kind), record))
+	}
+
+	if err := r.db.SavePersonalRecords(ctx, rows); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", records.UserID).Msg("failed to save personal records to database")
+		return err
+	}
+
+	return nil
+}
+
+// convertToRepoRecord конвертирует доменный рекорд в строку таблицы
+func (r *RecordRepositoryAdapter) convertToRepoRecord(userID int64, kind string, record domainModels.PersonalRecord) repoModels.PersonalRecord {
+	return repoModels.PersonalRecord{
+		UserID:     userID,
+		Kind:       kind,
+		Value:      record.Value,
+		AchievedOn: record.AchievedOn,
+	}
+}
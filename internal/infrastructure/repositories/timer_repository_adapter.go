@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	domainModels "azhumania/internal/domain/models"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/cache/redis"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TimerRepositoryAdapter адаптирует Redis к доменному интерфейсу таймеров отдыха. Таймеры
+// хранятся только в Redis: они живут минуты и переживают перезапуск бота вместе с Redis
+type TimerRepositoryAdapter struct {
+	cache  redis.ICache
+	logger *zerolog.Logger
+}
+
+// NewTimerRepositoryAdapter создает новый адаптер репозитория таймеров
+func NewTimerRepositoryAdapter(cache redis.ICache, logger *zerolog.Logger) repositories.TimerRepository {
+	return &TimerRepositoryAdapter{
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// Schedule ставит таймер пользователя, заменяя предыдущий
+func (r *TimerRepositoryAdapter) Schedule(ctx context.Context, timer domainModels.RestTimer) error {
+	if err := r.cache.ScheduleTimer(ctx, timer.UserID, timer.DueAt); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", timer.UserID).Msg("failed to schedule rest timer in cache")
+		return err
+	}
+
+	return nil
+}
+
+// Cancel снимает таймер пользователя
+func (r *TimerRepositoryAdapter) Cancel(ctx context.Context, userID int64) error {
+	if err := r.cache.CancelTimer(ctx, userID); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to cancel rest timer in cache")
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDue забирает сработавшие таймеры в порядке срабатывания
+func (r *TimerRepositoryAdapter) ClaimDue(ctx context.Context, now time.Time, limit int) ([]domainModels.RestTimer, error) {
+	due, err := r.cache.ClaimDueTimers(ctx, now, limit)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("failed to claim rest timers from cache")
+		return nil, err
+	}
+
+	timers := make([]domainModels.RestTimer, 0, len(due))
+	for userID, dueAt := range due {
+		timers = append(timers, domainModels.RestTimer{UserID: userID, DueAt: dueAt})
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].DueAt.Before(timers[j].DueAt)
+	})
+
+	return timers, nil
+}
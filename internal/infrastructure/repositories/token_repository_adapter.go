@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"azhumania/internal/domain/errors"
+	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
+	"azhumania/internal/repository/database/psql"
+	repoModels "azhumania/internal/repository/models"
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TokenRepositoryAdapter адаптирует хранилище токенов API к доменному интерфейсу
+type TokenRepositoryAdapter struct {
+	db     psql.IDatabase
+	logger *zerolog.Logger
+}
+
+// NewTokenRepositoryAdapter создает новый адаптер репозитория токенов
+func NewTokenRepositoryAdapter(db psql.IDatabase, logger *zerolog.Logger) repositories.TokenRepository {
+	return &TokenRepositoryAdapter{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Replace заменяет все токены пользователя новым
+func (r *TokenRepositoryAdapter) Replace(ctx context.Context, userID int64, tokenHash string) error {
+	err := r.db.ReplaceAPIToken(ctx, repoModels.APIToken{
+		TokenHash: tokenHash,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to save api token")
+		return err
+	}
+
+	return nil
+}
+
+// GetUserID возвращает ID пользователя, которому выдан токен
+func (r *TokenRepositoryAdapter) GetUserID(ctx context.Context, tokenHash string) (int64, error) {
+	token, err := r.db.GetAPIToken(ctx, tokenHash)
+	if err != nil {
+		if stderrors.Is(err, sql.ErrNoRows) {
+			return 0, errors.ErrInvalidToken
+		}
+		return 0, err
+	}
+
+	return token.UserID, nil
+}
+
+// DeleteByUser удаляет все токены пользователя
+func (r *TokenRepositoryAdapter) DeleteByUser(ctx context.Context, userID int64) error {
+	if err := r.db.DeleteAPITokens(ctx, userID); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", userID).Msg("failed to delete api tokens")
+		return err
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	domainModels "azhumania/internal/domain/models"
 	"azhumania/internal/domain/repositories"
+	"azhumania/internal/logging"
 	"azhumania/internal/repository/cache/redis"
 	"azhumania/internal/repository/database/psql"
 	repoModels "azhumania/internal/repository/models"
@@ -17,14 +18,16 @@ import (
 type UserRepositoryAdapter struct {
 	db     psql.IDatabase
 	cache  redis.ICache
+	bg     *Background
 	logger *zerolog.Logger
 }
 
 // NewUserRepositoryAdapter создает новый адаптер репозитория пользователей
-func NewUserRepositoryAdapter(db psql.IDatabase, cache redis.ICache, logger *zerolog.Logger) repositories.UserRepository {
+func NewUserRepositoryAdapter(db psql.IDatabase, cache redis.ICache, bg *Background, logger *zerolog.Logger) repositories.UserRepository {
 	return &UserRepositoryAdapter{
 		db:     db,
 		cache:  cache,
+		bg:     bg,
 		logger: logger,
 	}
 }
@@ -43,17 +46,16 @@ func (r *UserRepositoryAdapter) GetByTelegramID(ctx context.Context, telegramID
 		if err == sql.ErrNoRows {
 			return nil, err
 		}
-		r.logger.Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user from database")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("telegramID", telegramID).Msg("failed to get user from database")
 		return nil, err
 	}
 
 	// Сохраняем в кэш асинхронно
-	go func() {
-		ctx := context.Background()
+	r.bg.Go(ctx, func(ctx context.Context) {
 		if err := r.cache.SetUser(ctx, repoUser); err != nil {
-			r.logger.Error().Err(err).Int64("userID", repoUser.ID).Msg("failed to cache user")
+			logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", repoUser.ID).Msg("failed to cache user")
 		}
-	}()
+	})
 
 	return r.convertToDomainUser(repoUser), nil
 }
@@ -72,17 +74,16 @@ func (r *UserRepositoryAdapter) GetByID(ctx context.Context, id int64) (*domainM
 		if err == sql.ErrNoRows {
 			return nil, err
 		}
-		r.logger.Error().Err(err).Int64("userID", id).Msg("failed to get user from database")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", id).Msg("failed to get user from database")
 		return nil, err
 	}
 
 	// Сохраняем в кэш асинхронно
-	go func() {
-		ctx := context.Background()
+	r.bg.Go(ctx, func(ctx context.Context) {
 		if err := r.cache.SetUser(ctx, repoUser); err != nil {
-			r.logger.Error().Err(err).Int64("userID", repoUser.ID).Msg("failed to cache user")
+			logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", repoUser.ID).Msg("failed to cache user")
 		}
-	}()
+	})
 
 	return r.convertToDomainUser(repoUser), nil
 }
@@ -93,7 +94,7 @@ func (r *UserRepositoryAdapter) Create(ctx context.Context, user *domainModels.U
 
 	id, err := r.db.AddUser(ctx, repoUser)
 	if err != nil {
-		r.logger.Error().Err(err).Interface("user", user).Msg("failed to create user in database")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("telegramID", user.TelegramID).Msg("failed to create user in database")
 		return err
 	}
 
@@ -102,13 +103,12 @@ func (r *UserRepositoryAdapter) Create(ctx context.Context, user *domainModels.U
 	user.UpdatedAt = time.Now()
 
 	// Сохраняем в кэш асинхронно
-	go func() {
-		ctx := context.Background()
+	r.bg.Go(ctx, func(ctx context.Context) {
 		repoUser.ID = id
 		if err := r.cache.SetUser(ctx, repoUser); err != nil {
-			r.logger.Error().Err(err).Int64("userID", id).Msg("failed to cache new user")
+			logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", id).Msg("failed to cache new user")
 		}
-	}()
+	})
 
 	return nil
 }
@@ -118,17 +118,17 @@ func (r *UserRepositoryAdapter) Update(ctx context.Context, user *domainModels.U
 	user.UpdatedAt = time.Now()
 	repoUser := r.convertToRepoUser(user)
 
-	// Обновляем в БД (здесь нужно будет добавить метод Update в интерфейс БД)
-	// TODO: Добавить метод Update в psql.IDatabase
-	r.logger.Warn().Msg("Update method not implemented in database interface")
+	if err := r.db.UpdateUser(ctx, repoUser); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to update user in database")
+		return err
+	}
 
 	// Обновляем в кэше
-	go func() {
-		ctx := context.Background()
+	r.bg.Go(ctx, func(ctx context.Context) {
 		if err := r.cache.SetUser(ctx, repoUser); err != nil {
-			r.logger.Error().Err(err).Int64("userID", user.ID).Msg("failed to update user in cache")
+			logging.FromContext(ctx, r.logger).Error().Err(err).Int64("userID", user.ID).Msg("failed to update user in cache")
 		}
-	}()
+	})
 
 	return nil
 }
@@ -153,23 +153,72 @@ func (r *UserRepositoryAdapter) Exists(ctx context.Context, telegramID int64) (b
 	return repoUser.ID != 0, nil
 }
 
+// ListDigestSubscribers получает пользователей, подписанных на еженедельную сводку
+func (r *UserRepositoryAdapter) ListDigestSubscribers(ctx context.Context) ([]*domainModels.User, error) {
+	repoUsers, err := r.db.ListDigestSubscribers(ctx)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("failed to list digest subscribers from database")
+		return nil, err
+	}
+
+	users := make([]*domainModels.User, len(repoUsers))
+	for i, repoUser := range repoUsers {
+		users[i] = r.convertToDomainUser(repoUser)
+	}
+
+	return users, nil
+}
+
 // convertToDomainUser конвертирует репозиторную модель в доменную
 func (r *UserRepositoryAdapter) convertToDomainUser(repoUser repoModels.User) *domainModels.User {
 	return &domainModels.User{
-		ID:         repoUser.ID,
-		Phone:      repoUser.Phone,
-		NickName:   repoUser.NickName,
-		TelegramID: repoUser.ID, // Предполагаем, что ID в БД это TelegramID
-		CreatedAt:  time.Now(),  // TODO: Добавить поля CreatedAt/UpdatedAt в репозиторную модель
-		UpdatedAt:  time.Now(),
+		ID:           repoUser.ID,
+		Phone:        repoUser.Phone,
+		NickName:     repoUser.NickName,
+		TelegramID:   repoUser.ID, // Предполагаем, что ID в БД это TelegramID
+		Role:         convertToDomainRole(repoUser.Role),
+		BannedAt:     repoUser.BannedAt,
+		InactiveAt:   repoUser.InactiveAt,
+		RestTimer:    time.Duration(repoUser.RestTimerSeconds) * time.Second,
+		WeeklyDigest: repoUser.WeeklyDigest,
+		Timezone:     repoUser.Timezone,
+		DailyGoal:    repoUser.DailyGoal,
+		WeekStart:    convertToDomainWeekday(repoUser.WeekStart),
+		CreatedAt:    time.Now(), // TODO: Добавить поля CreatedAt/UpdatedAt в репозиторную модель
+		UpdatedAt:    time.Now(),
 	}
 }
 
 // convertToRepoUser конвертирует доменную модель в репозиторную
 func (r *UserRepositoryAdapter) convertToRepoUser(domainUser *domainModels.User) repoModels.User {
 	return repoModels.User{
-		ID:       domainUser.TelegramID, // Используем TelegramID как ID в БД
-		Phone:    domainUser.Phone,
-		NickName: domainUser.NickName,
+		ID:               domainUser.TelegramID, // Используем TelegramID как ID в БД
+		Phone:            domainUser.Phone,
+		NickName:         domainUser.NickName,
+		Role:             string(domainUser.Role),
+		BannedAt:         domainUser.BannedAt,
+		InactiveAt:       domainUser.InactiveAt,
+		RestTimerSeconds: int(domainUser.RestTimer / time.Second),
+		WeeklyDigest:     domainUser.WeeklyDigest,
+		Timezone:         domainUser.Timezone,
+		DailyGoal:        domainUser.DailyGoal,
+		WeekStart:        int(domainUser.WeekStart+6)%7 + 1,
+	}
+}
+
+// convertToDomainWeekday конвертирует день недели ISO 8601. В кэше до появления настройки
+// поля нет: такие пользователи получают неделю по умолчанию
+func convertToDomainWeekday(day int) time.Weekday {
+	if day < 1 || day > 7 {
+		return domainModels.DefaultWeekStart
+	}
+	return time.Weekday(day % 7)
+}
+
+// convertToDomainRole конвертирует роль из БД или кэша. В кэше до появления ролей поля нет
+func convertToDomainRole(role string) domainModels.Role {
+	if role == "" {
+		return domainModels.RoleUser
 	}
+	return domainModels.Role(role)
 }
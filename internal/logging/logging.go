@@ -0,0 +1,46 @@
+// Package logging настраивает zerolog и передает логгер обновления через контекст
+package logging
+
+import (
+	"azhumania/internal/config"
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// New создает логгер приложения, пишущий в out, по настройкам: уровень, формат вывода и маскирование
+func New(cfg config.Log, out io.Writer) (zerolog.Logger, error) {
+	level, err := zerolog.ParseLevel(cfg.Level)
+	if err != nil {
+		return zerolog.Logger{}, fmt.Errorf("parse log level: %w", err)
+	}
+
+	if cfg.Format == config.LogFormatConsole {
+		out = zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339}
+	}
+	// Маскирование работает с JSON, поэтому стоит перед ConsoleWriter
+	if cfg.Redact {
+		out = NewRedactWriter(out)
+	}
+
+	return zerolog.New(out).Level(level).With().Timestamp().Logger(), nil
+}
+
+type loggerKey struct{}
+
+// WithLogger сохраняет логгер в контексте. Все слои, получившие этот контекст,
+// пишут записи с его полями (update_id, user_id, chat_id)
+func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext возвращает логгер из контекста или fallback, если его там нет
+func FromContext(ctx context.Context, fallback *zerolog.Logger) *zerolog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok {
+		return logger
+	}
+	return fallback
+}
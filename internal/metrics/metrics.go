@@ -0,0 +1,147 @@
+// Package metrics содержит метрики Prometheus, которые собирают все слои приложения
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "azhumania"
+
+// Registry реестр метрик приложения
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	// UpdatesReceived количество полученных обновлений Telegram по типу
+	UpdatesReceived = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "updates_received_total",
+		Help:      "Telegram updates received, by update type.",
+	}, []string{"type"})
+
+	// UpdateQueueDepth количество обновлений, ожидающих обработки в очередях воркеров
+	UpdateQueueDepth = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "update_queue_depth",
+		Help:      "Telegram updates waiting in worker queues.",
+	})
+
+	// UpdateQueueWait время ожидания обновления в очереди
+	UpdateQueueWait = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "update_queue_wait_seconds",
+		Help:      "Time a Telegram update spent waiting in a worker queue.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// UpdateDuration полное время обработки обновления воркером, включая постановку ответа в очередь отправки
+	UpdateDuration = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "update_duration_seconds",
+		Help:      "Time spent processing a Telegram update, including queueing the reply.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// CommandsHandled количество обработанных команд
+	CommandsHandled = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_handled_total",
+		Help:      "Bot commands handled, by command.",
+	}, []string{"command"})
+
+	// HandlerDuration время обработки сообщения
+	HandlerDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "handler_duration_seconds",
+		Help:      "Time spent handling a message, by command.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"command"})
+
+	// ApproachesLogged количество записанных подходов
+	ApproachesLogged = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "approaches_logged_total",
+		Help:      "Pushup approaches logged.",
+	})
+
+	// PushupsLogged сумма отжиманий во всех записанных подходах
+	PushupsLogged = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pushups_logged_total",
+		Help:      "Pushups logged across all approaches.",
+	})
+
+	// JobRuns количество запусков фоновых задач по результату: ok или error
+	JobRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Background job runs, by job and result (ok, error).",
+	}, []string{"job", "result"})
+
+	// TelegramSendErrors количество ошибок при вызове Telegram Bot API
+	TelegramSendErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_send_errors_total",
+		Help:      "Failed Telegram Bot API calls, by outgoing action.",
+	}, []string{"action"})
+
+	// SendQueueDepth количество исходящих сообщений, ожидающих отправки
+	SendQueueDepth = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "send_queue_depth",
+		Help:      "Outgoing Telegram messages waiting in sender queues.",
+	})
+
+	// TelegramSendRetries количество повторных отправок по причине: rate_limited или transient
+	TelegramSendRetries = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "telegram_send_retries_total",
+		Help:      "Retried Telegram Bot API calls, by reason (rate_limited, transient).",
+	}, []string{"reason"})
+
+	// PostgresQueryDuration время выполнения запросов к PostgreSQL
+	PostgresQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "postgres_query_duration_seconds",
+		Help:      "PostgreSQL query latency, by repository method.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method"})
+
+	// CacheRequests количество обращений к кэшу Redis по результату: hit, miss или error
+	CacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redis_cache_requests_total",
+		Help:      "Redis cache lookups, by method and result (hit, miss, error).",
+	}, []string{"method", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler возвращает HTTP обработчик эндпоинта /metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObservePostgres записывает длительность запроса. Использование: defer metrics.ObservePostgres("GetUser", time.Now())
+func ObservePostgres(method string, started time.Time) {
+	PostgresQueryDuration.WithLabelValues(method).Observe(time.Since(started).Seconds())
+}
+
+// Результаты обращения к кэшу
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
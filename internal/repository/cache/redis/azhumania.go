@@ -10,6 +10,7 @@ func (r *repository) GetAzhumania(ctx context.Context, userID int64) ([]models.A
 	azhumania := models.Azhumania{UserID: userID}
 
 	data, err := r.cache.Get(ctx, azhumania.CacheKey()).Result()
+	observeCache("GetAzhumania", err)
 	if err != nil {
 		return nil, err
 	}
@@ -27,16 +28,21 @@ func (r *repository) GetAzhumania(ctx context.Context, userID int64) ([]models.A
 	return azhumaniaList, nil
 }
 
-func (r *repository) SetAzhumania(ctx context.Context, azhumania models.Azhumania) error {
+// SetAzhumania добавляет подходы одного пользователя в конец его списка в кэше
+func (r *repository) SetAzhumania(ctx context.Context, azhumania ...models.Azhumania) error {
+	if len(azhumania) == 0 {
+		return nil
+	}
+
 	// Получаем существующие записи
-	existingRecords, err := r.GetAzhumania(ctx, azhumania.UserID)
+	existingRecords, err := r.GetAzhumania(ctx, azhumania[0].UserID)
 	if err != nil {
 		// Если записи не найдены, создаем новый слайс
 		existingRecords = []models.Azhumania{}
 	}
 
-	// Добавляем новую запись
-	existingRecords = append(existingRecords, azhumania)
+	// Добавляем новые записи
+	existingRecords = append(existingRecords, azhumania...)
 
 	// Сохраняем обновленный слайс
 	data, err := json.Marshal(existingRecords)
@@ -44,5 +50,5 @@ func (r *repository) SetAzhumania(ctx context.Context, azhumania models.Azhumani
 		return err
 	}
 
-	return r.cache.Set(ctx, azhumania.CacheKey(), data, 0).Err()
+	return r.cache.Set(ctx, azhumania[0].CacheKey(), data, 0).Err()
 }
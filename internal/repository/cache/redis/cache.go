@@ -1,6 +1,10 @@
 package redis
 
 import (
+	"azhumania/internal/metrics"
+	"context"
+	"errors"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 )
@@ -24,3 +28,23 @@ func New(addr, username, password string, db int, logger *zerolog.Logger) ICache
 		logger: logger,
 	}
 }
+
+func (r *repository) Ping(ctx context.Context) error {
+	return r.cache.Ping(ctx).Err()
+}
+
+func (r *repository) Close() error {
+	return r.cache.Close()
+}
+
+// observeCache учитывает результат чтения из кэша: попадание, промах или ошибку
+func observeCache(method string, err error) {
+	result := metrics.CacheHit
+	switch {
+	case errors.Is(err, redis.Nil):
+		result = metrics.CacheMiss
+	case err != nil:
+		result = metrics.CacheError
+	}
+	metrics.CacheRequests.WithLabelValues(method, result).Inc()
+}
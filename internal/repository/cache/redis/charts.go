@@ -0,0 +1,23 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func (r *repository) SetChart(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	return r.cache.Set(ctx, "chart:"+id, data, ttl).Err()
+}
+
+// GetChart возвращает nil без ошибки, если картинки нет: промах здесь — обычная просроченная ссылка
+func (r *repository) GetChart(ctx context.Context, id string) ([]byte, error) {
+	data, err := r.cache.Get(ctx, "chart:"+id).Bytes()
+	observeCache("GetChart", err)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return data, err
+}
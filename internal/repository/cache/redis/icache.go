@@ -3,11 +3,19 @@ package redis
 import (
 	"azhumania/internal/repository/models"
 	"context"
+	"time"
 )
 
 type ICache interface {
 	IUsersCache
 	IAzhumaniaCache
+	IRateLimitCache
+	ITimersCache
+	IPrivacyCache
+	IChartsCache
+
+	Ping(context.Context) error
+	Close() error
 }
 
 type IUsersCache interface {
@@ -17,5 +25,24 @@ type IUsersCache interface {
 
 type IAzhumaniaCache interface {
 	GetAzhumania(context.Context, int64) ([]models.Azhumania, error)
-	SetAzhumania(context.Context, models.Azhumania) error
+	SetAzhumania(context.Context, ...models.Azhumania) error
+}
+
+type IRateLimitCache interface {
+	AllowRate(ctx context.Context, key string, every time.Duration, burst int) (bool, error)
+}
+
+type ITimersCache interface {
+	ScheduleTimer(ctx context.Context, userID int64, at time.Time) error
+	CancelTimer(ctx context.Context, userID int64) error
+	ClaimDueTimers(ctx context.Context, now time.Time, limit int) (map[int64]time.Time, error)
+}
+
+type IPrivacyCache interface {
+	DeleteUserData(ctx context.Context, userID int64) error
+}
+
+type IChartsCache interface {
+	SetChart(ctx context.Context, id string, data []byte, ttl time.Duration) error
+	GetChart(ctx context.Context, id string) ([]byte, error)
 }
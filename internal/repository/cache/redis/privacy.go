@@ -0,0 +1,25 @@
+package redis
+
+import (
+	"azhumania/internal/repository/models"
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// rateLimitScopes виды лимитов сообщений пользователя, см. ratelimit.Policy
+var rateLimitScopes = []string{"msg", "expensive", "notice"}
+
+// DeleteUserData удаляет из кэша все данные пользователя: профиль, подходы, таймер отдыха
+// и корзины лимитов частоты
+func (r *repository) DeleteUserData(ctx context.Context, userID int64) error {
+	pipe := r.cache.TxPipeline()
+	azhumania := models.Azhumania{UserID: userID}
+	pipe.Del(ctx, models.User{ID: userID}.CacheKey(), azhumania.CacheKey(), azhumania.LegacyCacheKey())
+	for _, scope := range rateLimitScopes {
+		pipe.Del(ctx, fmt.Sprintf("ratelimit:%s:%d", scope, userID))
+	}
+	pipe.ZRem(ctx, restTimersKey, strconv.FormatInt(userID, 10))
+	_, err := pipe.Exec(ctx)
+	return err
+}
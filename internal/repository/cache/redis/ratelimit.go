@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucket атомарно пополняет корзину по времени сервера Redis и забирает из нее токен.
+// ARGV[1] — интервал пополнения одного токена в миллисекундах, ARGV[2] — размер корзины
+var tokenBucket = redis.NewScript(`
+local every = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(data[1]) or burst
+local ts = tonumber(data[2]) or now
+
+tokens = math.min(burst, tokens + (now - ts) / every)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(every * burst))
+
+return allowed
+`)
+
+func (r *repository) AllowRate(ctx context.Context, key string, every time.Duration, burst int) (bool, error) {
+	allowed, err := tokenBucket.Run(ctx, r.cache, []string{"ratelimit:" + key}, every.Milliseconds(), burst).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return allowed == 1, nil
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// restTimersKey sorted set таймеров отдыха: элемент — ID пользователя, вес — время срабатывания в мс
+const restTimersKey = "rest_timers"
+
+// claimDue атомарно забирает сработавшие таймеры, чтобы один таймер не отправили два экземпляра.
+// ARGV[1] — текущее время в миллисекундах, ARGV[2] — сколько таймеров забрать
+var claimDue = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'WITHSCORES', 'LIMIT', 0, tonumber(ARGV[2]))
+for i = 1, #due, 2 do
+	redis.call('ZREM', KEYS[1], due[i])
+end
+return due
+`)
+
+func (r *repository) ScheduleTimer(ctx context.Context, userID int64, at time.Time) error {
+	return r.cache.ZAdd(ctx, restTimersKey, redis.Z{
+		Score:  float64(at.UnixMilli()),
+		Member: strconv.FormatInt(userID, 10),
+	}).Err()
+}
+
+func (r *repository) CancelTimer(ctx context.Context, userID int64) error {
+	return r.cache.ZRem(ctx, restTimersKey, strconv.FormatInt(userID, 10)).Err()
+}
+
+func (r *repository) ClaimDueTimers(ctx context.Context, now time.Time, limit int) (map[int64]time.Time, error) {
+	values, err := claimDue.Run(ctx, r.cache, []string{restTimersKey}, now.UnixMilli(), limit).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	timers := make(map[int64]time.Time, len(values)/2)
+	for i := 0; i+1 < len(values); i += 2 {
+		userID, err := strconv.ParseInt(values[i], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		dueAt, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return nil, err
+		}
+		timers[userID] = time.UnixMilli(int64(dueAt))
+	}
+
+	return timers, nil
+}
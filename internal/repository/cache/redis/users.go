@@ -10,6 +10,7 @@ func (r *repository) GetUser(ctx context.Context, userID int64) (models.User, er
 	user := models.User{ID: userID}
 
 	data, err := r.cache.Get(ctx, user.CacheKey()).Result()
+	observeCache("GetUser", err)
 	if err != nil {
 		return models.User{}, err
 	}
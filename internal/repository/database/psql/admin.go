@@ -0,0 +1,37 @@
+package psql
+
+import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"azhumania/internal/repository/models"
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// GetAdminStats считает пользователей и активность начиная с since одним запросом
+func (r *repository) GetAdminStats(ctx context.Context, since time.Time) (stats models.AdminStats, err error) {
+	defer metrics.ObservePostgres("GetAdminStats", time.Now())
+
+	query, args, err := r.builder.
+		Select().
+		Column(squirrel.Expr("(SELECT count(*) FROM users) AS users")).
+		Column(squirrel.Expr("(SELECT count(*) FROM users WHERE banned_at IS NOT NULL) AS banned_users")).
+		Column(squirrel.Expr("(SELECT count(*) FROM users WHERE inactive_at IS NOT NULL) AS inactive_users")).
+		Column(squirrel.Expr("(SELECT count(DISTINCT user_id) FROM azhumania WHERE date >= ?) AS active_today", since)).
+		Column(squirrel.Expr("(SELECT count(*) FROM azhumania WHERE date >= ?) AS approaches_today", since)).
+		Column(squirrel.Expr("(SELECT coalesce(sum(count), 0) FROM azhumania WHERE date >= ?) AS pushups_today", since)).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetAdminStats.ToSql")
+		return
+	}
+
+	if err = r.db.GetContext(ctx, &stats, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetAdminStats.GetContext")
+		return
+	}
+
+	return
+}
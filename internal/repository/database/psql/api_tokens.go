@@ -0,0 +1,109 @@
+package psql
+
+import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"azhumania/internal/repository/models"
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func (r *repository) GetAPIToken(ctx context.Context, tokenHash string) (token models.APIToken, err error) {
+	defer metrics.ObservePostgres("GetAPIToken", time.Now())
+
+	query, args, err := r.builder.
+		Select(
+			"token_hash",
+			"user_id",
+			"created_at",
+		).
+		From("api_tokens").
+		Where(squirrel.Eq{"token_hash": tokenHash}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetAPIToken.ToSql")
+		return
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&token)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetAPIToken.QueryRowxContext")
+		return
+	}
+
+	return
+}
+
+// ReplaceAPIToken удаляет старые токены пользователя и сохраняет новый в одной транзакции
+func (r *repository) ReplaceAPIToken(ctx context.Context, token models.APIToken) error {
+	defer metrics.ObservePostgres("ReplaceAPIToken", time.Now())
+
+	deleteQuery, deleteArgs, err := r.builder.
+		Delete("api_tokens").
+		Where(squirrel.Eq{"user_id": token.UserID}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ReplaceAPIToken.Delete.ToSql")
+		return err
+	}
+
+	insertQuery, insertArgs, err := r.builder.
+		Insert("api_tokens").
+		Columns(
+			"token_hash",
+			"user_id",
+			"created_at",
+		).
+		Values(
+			token.TokenHash,
+			token.UserID,
+			token.CreatedAt,
+		).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ReplaceAPIToken.Insert.ToSql")
+		return err
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ReplaceAPIToken.BeginTxx")
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ReplaceAPIToken.Delete.ExecContext")
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, insertQuery, insertArgs...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ReplaceAPIToken.Insert.ExecContext")
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// DeleteAPITokens удаляет все токены пользователя
+func (r *repository) DeleteAPITokens(ctx context.Context, userID int64) error {
+	defer metrics.ObservePostgres("DeleteAPITokens", time.Now())
+
+	query, args, err := r.builder.
+		Delete("api_tokens").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteAPITokens.ToSql")
+		return err
+	}
+
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteAPITokens.ExecContext")
+		return err
+	}
+
+	return nil
+}
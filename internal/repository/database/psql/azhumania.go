@@ -1,59 +1,94 @@
 package psql
 
 import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
 	"azhumania/internal/repository/models"
 	"context"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 )
 
 func (r *repository) GetAzhumania(ctx context.Context, userID int64) (azhumania []models.Azhumania, err error) {
+	defer metrics.ObservePostgres("GetAzhumania", time.Now())
+
 	query, args, err := r.builder.
 		Select(
+			"id",
 			"user_id",
 			"date",
 			"count",
+			"created_at",
 		).
 		From("azhumania").
 		Where(squirrel.Eq{"user_id": userID}).
-		OrderBy("date DESC").
+		OrderBy("date DESC", "id").
 		ToSql()
 	if err != nil {
-		r.logger.Error().Err(err).Msg("error repo GetAzhumania.ToSql")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetAzhumania.ToSql")
 		return
 	}
 
 	if err = r.db.SelectContext(ctx, &azhumania, query, args...); err != nil {
-		r.logger.Error().Err(err).Msg("error repo GetAzhumania.SelectContext")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetAzhumania.SelectContext")
 		return
 	}
 
 	return
 }
 
-func (r *repository) AddAzhumania(ctx context.Context, azhumania models.Azhumania) error {
-	query, args, err := r.builder.
-		Insert("azhumania").
-		Columns(
-			"user_id",
-			"date",
-			"count",
-		).
-		Values(
-			azhumania.UserID,
-			azhumania.Date,
-			azhumania.Count,
-		).
-		ToSql()
+// AddAzhumania записывает подходы в одной транзакции и возвращает их ID в том же порядке.
+// Подходы вставляются по одному: порядок строк RETURNING у многострочного INSERT не гарантирован
+func (r *repository) AddAzhumania(ctx context.Context, azhumania []models.Azhumania) ([]int64, error) {
+	defer metrics.ObservePostgres("AddAzhumania", time.Now())
+
+	if len(azhumania) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		r.logger.Error().Err(err).Msg("error repo AddAzhumania.ToSql")
-		return err
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo AddAzhumania.BeginTxx")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ids := make([]int64, 0, len(azhumania))
+	for _, approach := range azhumania {
+		query, args, err := r.builder.
+			Insert("azhumania").
+			Columns(
+				"user_id",
+				"date",
+				"count",
+				"created_at",
+			).
+			Values(
+				approach.UserID,
+				approach.Date,
+				approach.Count,
+				approach.CreatedAt,
+			).
+			Suffix("RETURNING id").
+			ToSql()
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo AddAzhumania.ToSql")
+			return nil, err
+		}
+
+		var id int64
+		if err = tx.QueryRowxContext(ctx, query, args...).Scan(&id); err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo AddAzhumania.QueryRowxContext")
+			return nil, err
+		}
+		ids = append(ids, id)
 	}
 
-	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
-		r.logger.Error().Err(err).Msg("error repo AddAzhumania.ExecContext")
-		return err
+	if err = tx.Commit(); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo AddAzhumania.Commit")
+		return nil, err
 	}
 
-	return nil
+	return ids, nil
 }
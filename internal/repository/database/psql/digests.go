@@ -0,0 +1,44 @@
+package psql
+
+import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"context"
+	"time"
+)
+
+// MarkWeeklyDigestSent отмечает сводку за неделю отправленной. Возвращает false, если она уже была отмечена
+func (r *repository) MarkWeeklyDigestSent(ctx context.Context, userID int64, weekStart time.Time) (bool, error) {
+	defer metrics.ObservePostgres("MarkWeeklyDigestSent", time.Now())
+
+	query, args, err := r.builder.
+		Insert("weekly_digests").
+		Columns(
+			"user_id",
+			"week_start",
+		).
+		Values(
+			userID,
+			weekStart,
+		).
+		Suffix("ON CONFLICT (user_id, week_start) DO NOTHING").
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo MarkWeeklyDigestSent.ToSql")
+		return false, err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo MarkWeeklyDigestSent.ExecContext")
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo MarkWeeklyDigestSent.RowsAffected")
+		return false, err
+	}
+
+	return inserted > 0, nil
+}
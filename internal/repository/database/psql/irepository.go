@@ -3,19 +3,62 @@ package psql
 import (
 	"azhumania/internal/repository/models"
 	"context"
+	"time"
 )
 
 type IDatabase interface {
 	IUsersDatabase
 	IAzhumaniaDatabase
+	IAPITokensDatabase
+	IAdminDatabase
+	IPersonalRecordsDatabase
+	IProgramsDatabase
+	IDigestsDatabase
+	IPrivacyDatabase
+
+	Ping(context.Context) error
+	Close() error
 }
 
 type IUsersDatabase interface {
 	GetUser(context.Context, int64) (models.User, error)
 	AddUser(context.Context, models.User) (int64, error)
+	UpdateUser(context.Context, models.User) error
+	ListActiveUserIDs(context.Context) ([]int64, error)
+	ListDigestSubscribers(context.Context) ([]models.User, error)
 }
 
 type IAzhumaniaDatabase interface {
 	GetAzhumania(context.Context, int64) ([]models.Azhumania, error)
-	AddAzhumania(context.Context, models.Azhumania) error
+	AddAzhumania(context.Context, []models.Azhumania) ([]int64, error)
+}
+
+type IAPITokensDatabase interface {
+	GetAPIToken(context.Context, string) (models.APIToken, error)
+	ReplaceAPIToken(context.Context, models.APIToken) error
+	DeleteAPITokens(context.Context, int64) error
+}
+
+type IAdminDatabase interface {
+	GetAdminStats(ctx context.Context, since time.Time) (models.AdminStats, error)
+}
+
+type IPersonalRecordsDatabase interface {
+	GetPersonalRecords(context.Context, int64) ([]models.PersonalRecord, error)
+	SavePersonalRecords(context.Context, []models.PersonalRecord) error
+}
+
+type IProgramsDatabase interface {
+	GetProgramEnrollment(context.Context, int64) (models.ProgramEnrollment, error)
+	SaveProgramEnrollment(context.Context, models.ProgramEnrollment) error
+	DeleteProgramEnrollment(context.Context, int64) (bool, error)
+}
+
+type IDigestsDatabase interface {
+	MarkWeeklyDigestSent(ctx context.Context, userID int64, weekStart time.Time) (bool, error)
+}
+
+type IPrivacyDatabase interface {
+	GetUserDataSummary(context.Context, int64) (models.UserDataSummary, error)
+	DeleteUserData(context.Context, int64) (models.UserDataSummary, error)
 }
@@ -0,0 +1,128 @@
+package psql
+
+import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"azhumania/internal/repository/models"
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// userDataTables таблицы с данными пользователя: колонка с его ID и поле UserDataSummary
+// с количеством строк. users удаляется последней
+var userDataTables = []struct {
+	table  string
+	column string
+	field  string
+}{
+	{"azhumania", "user_id", "approaches"},
+	{"api_tokens", "user_id", "api_tokens"},
+	{"personal_records", "user_id", "personal_records"},
+	{"program_enrollments", "user_id", "program_enrollments"},
+	{"weekly_digests", "user_id", "weekly_digests"},
+	{"users", "id", "users"},
+}
+
+// GetUserDataSummary считает данные пользователя во всех таблицах одним запросом
+func (r *repository) GetUserDataSummary(ctx context.Context, userID int64) (summary models.UserDataSummary, err error) {
+	defer metrics.ObservePostgres("GetUserDataSummary", time.Now())
+
+	builder := r.builder.
+		Select().
+		Column(squirrel.Expr("(SELECT coalesce(sum(count), 0) FROM azhumania WHERE user_id = ?) AS pushups", userID)).
+		Column(squirrel.Expr("(SELECT min(date) FROM azhumania WHERE user_id = ?) AS first_date", userID)).
+		Column(squirrel.Expr("(SELECT max(date) FROM azhumania WHERE user_id = ?) AS last_date", userID))
+	for _, t := range userDataTables {
+		builder = builder.Column(squirrel.Expr("(SELECT count(*) FROM "+t.table+" WHERE "+t.column+" = ?) AS "+t.field, userID))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetUserDataSummary.ToSql")
+		return
+	}
+
+	if err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&summary); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetUserDataSummary.QueryRowxContext")
+		return
+	}
+
+	return
+}
+
+// DeleteUserData удаляет пользователя и все его данные в одной транзакции и записывает
+// удаление в журнал account_deletions. Возвращает количество удаленных строк по таблицам
+func (r *repository) DeleteUserData(ctx context.Context, userID int64) (deleted models.UserDataSummary, err error) {
+	defer metrics.ObservePostgres("DeleteUserData", time.Now())
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteUserData.BeginTxx")
+		return
+	}
+	defer tx.Rollback()
+
+	counts := make(map[string]int, len(userDataTables))
+	for _, t := range userDataTables {
+		query, args, err := r.builder.
+			Delete(t.table).
+			Where(squirrel.Eq{t.column: userID}).
+			ToSql()
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Str("table", t.table).Msg("error repo DeleteUserData.Delete.ToSql")
+			return deleted, err
+		}
+
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Str("table", t.table).Msg("error repo DeleteUserData.Delete.ExecContext")
+			return deleted, err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			logging.FromContext(ctx, r.logger).Error().Err(err).Str("table", t.table).Msg("error repo DeleteUserData.RowsAffected")
+			return deleted, err
+		}
+		counts[t.field] = int(rows)
+	}
+
+	deleted = models.UserDataSummary{
+		Users:              counts["users"],
+		Approaches:         counts["approaches"],
+		APITokens:          counts["api_tokens"],
+		PersonalRecords:    counts["personal_records"],
+		ProgramEnrollments: counts["program_enrollments"],
+		WeeklyDigests:      counts["weekly_digests"],
+	}
+
+	query, args, err := r.builder.
+		Insert("account_deletions").
+		Columns(
+			"user_id",
+			"approaches",
+		).
+		Values(
+			userID,
+			deleted.Approaches,
+		).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteUserData.Audit.ToSql")
+		return
+	}
+
+	if _, err = tx.ExecContext(ctx, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteUserData.Audit.ExecContext")
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteUserData.Commit")
+		return
+	}
+
+	return
+}
@@ -0,0 +1,125 @@
+package psql
+
+import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"azhumania/internal/repository/models"
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func (r *repository) GetProgramEnrollment(ctx context.Context, userID int64) (enrollment models.ProgramEnrollment, err error) {
+	defer metrics.ObservePostgres("GetProgramEnrollment", time.Now())
+
+	query, args, err := r.builder.
+		Select(
+			"user_id",
+			"program_id",
+			"initial_max",
+			"week",
+			"day",
+			"misses",
+			"day_started_on",
+			"last_completed_on",
+			"finished_at",
+			"enrolled_at",
+		).
+		From("program_enrollments").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetProgramEnrollment.ToSql")
+		return
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&enrollment)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetProgramEnrollment.QueryRowxContext")
+		return
+	}
+
+	return
+}
+
+// SaveProgramEnrollment создает или заменяет участие пользователя в программе
+func (r *repository) SaveProgramEnrollment(ctx context.Context, enrollment models.ProgramEnrollment) error {
+	defer metrics.ObservePostgres("SaveProgramEnrollment", time.Now())
+
+	query, args, err := r.builder.
+		Insert("program_enrollments").
+		Columns(
+			"user_id",
+			"program_id",
+			"initial_max",
+			"week",
+			"day",
+			"misses",
+			"day_started_on",
+			"last_completed_on",
+			"finished_at",
+			"enrolled_at",
+		).
+		Values(
+			enrollment.UserID,
+			enrollment.ProgramID,
+			enrollment.InitialMax,
+			enrollment.Week,
+			enrollment.Day,
+			enrollment.Misses,
+			enrollment.DayStartedOn,
+			enrollment.LastCompletedOn,
+			enrollment.FinishedAt,
+			enrollment.EnrolledAt,
+		).
+		Suffix(`ON CONFLICT (user_id) DO UPDATE SET
+			program_id = EXCLUDED.program_id,
+			initial_max = EXCLUDED.initial_max,
+			week = EXCLUDED.week,
+			day = EXCLUDED.day,
+			misses = EXCLUDED.misses,
+			day_started_on = EXCLUDED.day_started_on,
+			last_completed_on = EXCLUDED.last_completed_on,
+			finished_at = EXCLUDED.finished_at,
+			enrolled_at = EXCLUDED.enrolled_at`).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo SaveProgramEnrollment.ToSql")
+		return err
+	}
+
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo SaveProgramEnrollment.ExecContext")
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) DeleteProgramEnrollment(ctx context.Context, userID int64) (bool, error) {
+	defer metrics.ObservePostgres("DeleteProgramEnrollment", time.Now())
+
+	query, args, err := r.builder.
+		Delete("program_enrollments").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteProgramEnrollment.ToSql")
+		return false, err
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteProgramEnrollment.ExecContext")
+		return false, err
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo DeleteProgramEnrollment.RowsAffected")
+		return false, err
+	}
+
+	return deleted > 0, nil
+}
@@ -0,0 +1,78 @@
+package psql
+
+import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
+	"azhumania/internal/repository/models"
+	"context"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func (r *repository) GetPersonalRecords(ctx context.Context, userID int64) (records []models.PersonalRecord, err error) {
+	defer metrics.ObservePostgres("GetPersonalRecords", time.Now())
+
+	query, args, err := r.builder.
+		Select(
+			"user_id",
+			"kind",
+			"value",
+			"achieved_on",
+		).
+		From("personal_records").
+		Where(squirrel.Eq{"user_id": userID}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetPersonalRecords.ToSql")
+		return
+	}
+
+	if err = r.db.SelectContext(ctx, &records, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetPersonalRecords.SelectContext")
+		return
+	}
+
+	return
+}
+
+// SavePersonalRecords записывает рекорды одним запросом: существующие строки обновляются
+func (r *repository) SavePersonalRecords(ctx context.Context, records []models.PersonalRecord) error {
+	defer metrics.ObservePostgres("SavePersonalRecords", time.Now())
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	builder := r.builder.
+		Insert("personal_records").
+		Columns(
+			"user_id",
+			"kind",
+			"value",
+			"achieved_on",
+		)
+	for _, record := range records {
+		builder = builder.Values(
+			record.UserID,
+			record.Kind,
+			record.Value,
+			record.AchievedOn,
+		)
+	}
+
+	query, args, err := builder.
+		Suffix("ON CONFLICT (user_id, kind) DO UPDATE SET value = EXCLUDED.value, achieved_on = EXCLUDED.achieved_on, updated_at = now()").
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo SavePersonalRecords.ToSql")
+		return err
+	}
+
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo SavePersonalRecords.ExecContext")
+		return err
+	}
+
+	return nil
+}
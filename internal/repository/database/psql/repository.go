@@ -1,6 +1,8 @@
 package psql
 
 import (
+	"context"
+
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
@@ -30,3 +32,11 @@ func New(dsn string, logger *zerolog.Logger) (IDatabase, error) {
 		logger:  logger,
 	}, nil
 }
+
+func (r *repository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func (r *repository) Close() error {
+	return r.db.Close()
+}
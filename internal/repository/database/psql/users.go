@@ -1,29 +1,43 @@
 package psql
 
 import (
+	"azhumania/internal/logging"
+	"azhumania/internal/metrics"
 	"azhumania/internal/repository/models"
 	"context"
+	"time"
+
 	"github.com/Masterminds/squirrel"
 )
 
 func (r *repository) GetUser(ctx context.Context, userID int64) (user models.User, err error) {
+	defer metrics.ObservePostgres("GetUser", time.Now())
+
 	query, args, err := r.builder.
 		Select(
 			"id",
 			"phone",
 			"nickname",
+			"role",
+			"banned_at",
+			"inactive_at",
+			"rest_timer_seconds",
+			"weekly_digest",
+			"timezone",
+			"daily_goal",
+			"week_start",
 		).
 		From("users").
 		Where(squirrel.Eq{"id": userID}).
 		ToSql()
 	if err != nil {
-		r.logger.Error().Err(err).Msg("error repo GetUser.ToSql")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetUser.ToSql")
 		return
 	}
 
 	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&user)
 	if err != nil {
-		r.logger.Error().Err(err).Msg("error repo GetUser.QueryxContext")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo GetUser.QueryxContext")
 		return
 	}
 
@@ -31,28 +45,131 @@ func (r *repository) GetUser(ctx context.Context, userID int64) (user models.Use
 }
 
 func (r *repository) AddUser(ctx context.Context, user models.User) (int64, error) {
+	defer metrics.ObservePostgres("AddUser", time.Now())
+
 	query, args, err := r.builder.
 		Insert("users").
 		Columns(
 			"phone",
 			"nickname",
+			"role",
+			"rest_timer_seconds",
+			"weekly_digest",
+			"timezone",
+			"daily_goal",
+			"week_start",
 		).
 		Values(
 			user.Phone,
 			user.NickName,
+			user.Role,
+			user.RestTimerSeconds,
+			user.WeeklyDigest,
+			user.Timezone,
+			user.DailyGoal,
+			user.WeekStart,
 		).
 		Suffix("RETURNING id").
 		ToSql()
 	if err != nil {
-		r.logger.Error().Err(err).Msg("error repo AddUser.ToSql")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo AddUser.ToSql")
 		return 0, err
 	}
 
 	_, err = r.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		r.logger.Error().Err(err).Msg("error repo AddUser.ExecContext")
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo AddUser.ExecContext")
 		return 0, err
 	}
 
 	return user.ID, nil
 }
+
+// UpdateUser сохраняет имя, роль, блокировку, доступность и настройки пользователя
+func (r *repository) UpdateUser(ctx context.Context, user models.User) error {
+	defer metrics.ObservePostgres("UpdateUser", time.Now())
+
+	query, args, err := r.builder.
+		Update("users").
+		Set("phone", user.Phone).
+		Set("nickname", user.NickName).
+		Set("role", user.Role).
+		Set("banned_at", user.BannedAt).
+		Set("inactive_at", user.InactiveAt).
+		Set("rest_timer_seconds", user.RestTimerSeconds).
+		Set("weekly_digest", user.WeeklyDigest).
+		Set("timezone", user.Timezone).
+		Set("daily_goal", user.DailyGoal).
+		Set("week_start", user.WeekStart).
+		Where(squirrel.Eq{"id": user.ID}).
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo UpdateUser.ToSql")
+		return err
+	}
+
+	if _, err = r.db.ExecContext(ctx, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo UpdateUser.ExecContext")
+		return err
+	}
+
+	return nil
+}
+
+// ListActiveUserIDs возвращает ID всех незаблокированных пользователей, которым доставляются сообщения
+func (r *repository) ListActiveUserIDs(ctx context.Context) (ids []int64, err error) {
+	defer metrics.ObservePostgres("ListActiveUserIDs", time.Now())
+
+	query, args, err := r.builder.
+		Select("id").
+		From("users").
+		Where(squirrel.Eq{"banned_at": nil, "inactive_at": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ListActiveUserIDs.ToSql")
+		return
+	}
+
+	if err = r.db.SelectContext(ctx, &ids, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ListActiveUserIDs.SelectContext")
+		return
+	}
+
+	return
+}
+
+// ListDigestSubscribers возвращает незаблокированных и доступных пользователей, подписанных на еженедельную сводку
+func (r *repository) ListDigestSubscribers(ctx context.Context) (users []models.User, err error) {
+	defer metrics.ObservePostgres("ListDigestSubscribers", time.Now())
+
+	query, args, err := r.builder.
+		Select(
+			"id",
+			"phone",
+			"nickname",
+			"role",
+			"banned_at",
+			"inactive_at",
+			"rest_timer_seconds",
+			"weekly_digest",
+			"timezone",
+			"daily_goal",
+			"week_start",
+		).
+		From("users").
+		Where(squirrel.Eq{"weekly_digest": true, "banned_at": nil, "inactive_at": nil}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ListDigestSubscribers.ToSql")
+		return
+	}
+
+	if err = r.db.SelectContext(ctx, &users, query, args...); err != nil {
+		logging.FromContext(ctx, r.logger).Error().Err(err).Msg("error repo ListDigestSubscribers.SelectContext")
+		return
+	}
+
+	return
+}
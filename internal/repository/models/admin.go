@@ -0,0 +1,11 @@
+package models
+
+// AdminStats сводные показатели бота для администраторов
+type AdminStats struct {
+	Users           int `db:"users"`
+	BannedUsers     int `db:"banned_users"`
+	InactiveUsers   int `db:"inactive_users"`
+	ActiveToday     int `db:"active_today"`
+	ApproachesToday int `db:"approaches_today"`
+	PushupsToday    int `db:"pushups_today"`
+}
@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+type APIToken struct {
+	TokenHash string    `json:"token_hash" db:"token_hash"`
+	UserID    int64     `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
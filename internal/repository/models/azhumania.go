@@ -5,12 +5,22 @@ import (
 	"time"
 )
 
+// Azhumania один подход: строка таблицы azhumania
 type Azhumania struct {
-	UserID int64     `json:"user_id" db:"user_id"`
-	Date   time.Time `json:"date" db:"date"`
-	Count  int       `json:"count" db:"count"`
+	ID        int64      `json:"id" db:"id"`
+	UserID    int64      `json:"user_id" db:"user_id"`
+	Date      time.Time  `json:"date" db:"date"`
+	Count     int        `json:"count" db:"count"`
+	CreatedAt *time.Time `json:"created_at,omitempty" db:"created_at"` // nil у подходов, записанных до 008_approach_times.sql
 }
 
+// CacheKey ключ списка подходов пользователя. Версия в ключе отделяет его от списков, записанных
+// до 008_approach_times.sql, где у подходов не было ID
 func (a Azhumania) CacheKey() string {
+	return fmt.Sprintf("azhumania:v2:%d", a.UserID)
+}
+
+// LegacyCacheKey ключ списка подходов до 008_approach_times.sql. Не читается, только удаляется
+func (a Azhumania) LegacyCacheKey() string {
 	return fmt.Sprintf("azhumania:%d", a.UserID)
 }
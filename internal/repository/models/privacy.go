@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// UserDataSummary сколько данных пользователя хранится в каждой таблице
+type UserDataSummary struct {
+	Users              int        `db:"users"`
+	Approaches         int        `db:"approaches"`
+	Pushups            int        `db:"pushups"`
+	FirstDate          *time.Time `db:"first_date"`
+	LastDate           *time.Time `db:"last_date"`
+	APITokens          int        `db:"api_tokens"`
+	PersonalRecords    int        `db:"personal_records"`
+	ProgramEnrollments int        `db:"program_enrollments"`
+	WeeklyDigests      int        `db:"weekly_digests"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+type ProgramEnrollment struct {
+	UserID          int64      `json:"user_id" db:"user_id"`
+	ProgramID       string     `json:"program_id" db:"program_id"`
+	InitialMax      int        `json:"initial_max" db:"initial_max"`
+	Week            int        `json:"week" db:"week"`
+	Day             int        `json:"day" db:"day"`
+	Misses          int        `json:"misses" db:"misses"`
+	DayStartedOn    *time.Time `json:"day_started_on,omitempty" db:"day_started_on"`
+	LastCompletedOn *time.Time `json:"last_completed_on,omitempty" db:"last_completed_on"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	EnrolledAt      time.Time  `json:"enrolled_at" db:"enrolled_at"`
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type PersonalRecord struct {
+	UserID     int64     `json:"user_id" db:"user_id"`
+	Kind       string    `json:"kind" db:"kind"`
+	Value      int       `json:"value" db:"value"`
+	AchievedOn time.Time `json:"achieved_on" db:"achieved_on"`
+}
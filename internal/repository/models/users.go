@@ -1,11 +1,24 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type User struct {
-	ID       int64  `json:"id" db:"id"`
-	Phone    string `json:"phone" db:"phone"`
-	NickName string `json:"nickname" db:"nickname"`
+	ID       int64      `json:"id" db:"id"`
+	Phone    string     `json:"phone" db:"phone"`
+	NickName string     `json:"nickname" db:"nickname"`
+	Role     string     `json:"role,omitempty" db:"role"`
+	BannedAt *time.Time `json:"banned_at,omitempty" db:"banned_at"`
+	// InactiveAt время, когда Telegram перестал доставлять сообщения пользователю
+	InactiveAt *time.Time `json:"inactive_at,omitempty" db:"inactive_at"`
+	// RestTimerSeconds интервал таймера отдыха, 0 — таймер выключен
+	RestTimerSeconds int    `json:"rest_timer_seconds,omitempty" db:"rest_timer_seconds"`
+	WeeklyDigest     bool   `json:"weekly_digest,omitempty" db:"weekly_digest"`
+	Timezone         string `json:"timezone,omitempty" db:"timezone"` // имя IANA, пусто — UTC
+	DailyGoal        int    `json:"daily_goal,omitempty" db:"daily_goal"`
+	WeekStart        int    `json:"week_start,omitempty" db:"week_start"` // день ISO 8601, 0 в кэше до появления поля
 }
 
 func (u User) CacheKey() string {
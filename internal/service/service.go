@@ -1,13 +1,23 @@
 package service
 
 import (
+	"azhumania/internal/api"
 	"azhumania/internal/application/handlers"
+	"azhumania/internal/application/ratelimit"
+	"azhumania/internal/application/response"
+	"azhumania/internal/application/router"
+	"azhumania/internal/application/scheduler"
 	"azhumania/internal/application/services"
+	"azhumania/internal/config"
+	infraRateLimit "azhumania/internal/infrastructure/ratelimit"
 	infraRepos "azhumania/internal/infrastructure/repositories"
 	"azhumania/internal/repository/cache/redis"
 	"azhumania/internal/repository/database/psql"
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rs/zerolog"
@@ -15,46 +25,211 @@ import (
 
 var _ IService = &service{}
 
+// HealthCheck проверка доступности одной зависимости сервиса. HTTP адаптер превращает проверки
+// в /healthz и /readyz
+type HealthCheck struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
 type IService interface {
-	Handle(msg *tgbotapi.Message) (string, interface{})
+	Handle(ctx context.Context, msg *tgbotapi.Message) *response.Response
+
+	// Commands возвращает команды для меню бота в Telegram
+	Commands() []tgbotapi.BotCommand
+
+	// HandleInline отвечает на inline запрос
+	HandleInline(ctx context.Context, query *tgbotapi.InlineQuery) *response.InlineAnswer
+
+	// APIHandler возвращает обработчик REST API (/api/v1/...)
+	APIHandler() http.Handler
+
+	// ChartsHandler возвращает обработчик картинок графиков inline режима (/charts/...)
+	ChartsHandler() http.Handler
+
+	// MarkUnreachable отмечает пользователя, которому Telegram не доставляет сообщения:
+	// рассылки и сводки ему больше не отправляются, пока он снова не напишет боту
+	MarkUnreachable(ctx context.Context, telegramID int64)
+
+	// HealthChecks возвращает проверки доступности хранилищ для /healthz и /readyz
+	HealthChecks() []HealthCheck
+
+	// RunJobs запускает фоновые задачи и блокируется, пока не будет отменен ctx.
+	// Сообщения задач доставляет sender
+	RunJobs(ctx context.Context, sender scheduler.Sender)
+
+	// Close дожидается фоновых операций и закрывает соединения с хранилищами
+	Close(ctx context.Context) error
 }
 
 type service struct {
 	messageHandler *handlers.MessageHandler
+	inlineHandler  *handlers.InlineHandler
+	userService    *services.UserService
+	router         *router.Router
+	scheduler      *scheduler.Scheduler
+	apiHandler     http.Handler
+	chartsHandler  http.Handler
+	db             psql.IDatabase
+	cache          redis.ICache
+	bg             *infraRepos.Background
 	logger         *zerolog.Logger
 }
 
-func New(psql_dsn, redis_host, redis_username, redis_password string, redis_db int, logger *zerolog.Logger) (IService, error) {
+// startupTimeout ограничивает время проверки хранилищ при запуске
+const startupTimeout = 10 * time.Second
+
+func New(ctx context.Context, cfg *config.Config, logger *zerolog.Logger) (IService, error) {
 	// Инициализируем репозитории
-	db, err := psql.New(psql_dsn, logger)
+	db, err := psql.New(cfg.Postgres.DSN, logger)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("connect to postgres: %w", err)
 	}
 
-	cache := redis.New(redis_host, redis_username, redis_password, redis_db, logger)
-	if cache == nil {
-		return nil, errors.New("redis connection error")
+	cache := redis.New(cfg.Redis.Addr, cfg.Redis.Username, cfg.Redis.Password, cfg.Redis.DB, logger)
+
+	// Клиент Redis подключается лениво, поэтому проверяем соединение сразу,
+	// чтобы не запускать бота, который на каждое сообщение отвечает ошибкой
+	pingCtx, cancel := context.WithTimeout(ctx, startupTimeout)
+	defer cancel()
+	if err := cache.Ping(pingCtx); err != nil {
+		cache.Close()
+		db.Close()
+		return nil, fmt.Errorf("connect to redis: %w", err)
 	}
 
 	// Создаем адаптеры репозиториев
-	userRepo := infraRepos.NewUserRepositoryAdapter(db, cache, logger)
-	pushupRepo := infraRepos.NewPushupRepositoryAdapter(db, cache, logger)
+	bg := infraRepos.NewBackground()
+	userRepo := infraRepos.NewUserRepositoryAdapter(db, cache, bg, logger)
+	pushupRepo := infraRepos.NewPushupRepositoryAdapter(db, cache, bg, logger)
+	tokenRepo := infraRepos.NewTokenRepositoryAdapter(db, logger)
+	adminRepo := infraRepos.NewAdminRepositoryAdapter(db, logger)
+	recordRepo := infraRepos.NewRecordRepositoryAdapter(db, logger)
+	programRepo := infraRepos.NewProgramRepositoryAdapter(db, logger)
+	timerRepo := infraRepos.NewTimerRepositoryAdapter(cache, logger)
+	digestRepo := infraRepos.NewDigestRepositoryAdapter(db, logger)
+	privacyRepo := infraRepos.NewPrivacyRepositoryAdapter(db, cache, bg, logger)
+	chartRepo := infraRepos.NewChartRepositoryAdapter(cache, logger)
 
 	// Создаем сервисы
-	userService := services.NewUserService(userRepo, logger)
-	pushupService := services.NewPushupService(pushupRepo, logger)
+	userService := services.NewUserService(userRepo, cfg.Admins, logger)
+	pushupService := services.NewPushupService(pushupRepo, recordRepo, logger)
+	tokenService := services.NewTokenService(tokenRepo, logger)
+	adminService := services.NewAdminService(userRepo, adminRepo, tokenRepo, logger)
+	programService := services.NewProgramService(programRepo, pushupService, services.DefaultPrograms(), logger)
+	timerService := services.NewTimerService(timerRepo, userRepo, logger)
+	digestService := services.NewDigestService(userRepo, digestRepo, pushupService, logger)
+	privacyService := services.NewPrivacyService(privacyRepo, logger)
+	shareService := services.NewShareService(chartRepo, pushupService, digestService, logger)
 
 	// Создаем обработчики
-	commandHandler := handlers.NewCommandHandler(userService, pushupService, logger)
-	messageHandler := handlers.NewMessageHandler(userService, pushupService, commandHandler, logger)
+	commandRouter := router.New(logger)
+	commandHandler := handlers.NewCommandHandler(userService, pushupService, tokenService, programService, commandRouter, logger)
+	handlers.NewDayHandler(pushupService, commandRouter, logger)
+	handlers.NewProgramHandler(programService, commandRouter, logger)
+	timerHandler := handlers.NewTimerHandler(timerService, commandRouter, logger)
+	digestHandler := handlers.NewDigestHandler(digestService, userService, commandRouter, logger)
+	limiter := newRateLimiter(cfg.RateLimit, cache)
+	rateLimit := newRateLimitPolicy(cfg.RateLimit, limiter, logger)
+	inlineHandler := handlers.NewInlineHandler(userService, shareService, rateLimit, cfg.HTTP.PublicURL, logger)
+	handlers.NewPrivacyHandler(privacyService, inlineHandler, commandRouter, logger)
+	handlers.NewAdminHandler(adminService, pushupService, commandRouter, logger)
+	messageHandler := handlers.NewMessageHandler(userService, pushupService, programService, timerService, commandHandler, commandRouter, rateLimit, logger)
+
+	// Создаем фоновые задачи
+	jobs := scheduler.New(logger)
+	jobs.Register(timerHandler.Job())
+	jobs.Register(digestHandler.Job())
 
 	return &service{
 		messageHandler: messageHandler,
+		inlineHandler:  inlineHandler,
+		userService:    userService,
+		router:         commandRouter,
+		scheduler:      jobs,
+		apiHandler:     api.NewREST(pushupService, tokenService, userService, limiter, ratelimit.PerMinute(cfg.RateLimit.APIPerMinute, cfg.RateLimit.APIBurst), logger),
+		chartsHandler:  api.NewCharts(shareService, logger),
+		db:             db,
+		cache:          cache,
+		bg:             bg,
 		logger:         logger,
 	}, nil
 }
 
-func (s *service) Handle(msg *tgbotapi.Message) (string, interface{}) {
-	ctx := context.Background()
+// newRateLimiter создает хранилище лимитов частоты для бота и REST API, nil если ограничение выключено
+func newRateLimiter(cfg config.RateLimit, cache redis.ICache) ratelimit.Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Backend == config.RateLimitRedis {
+		return infraRateLimit.NewRedisLimiter(cache)
+	}
+	return ratelimit.NewMemoryLimiter()
+}
+
+// newRateLimitPolicy создает политику ограничения частоты сообщений, nil если ограничение выключено
+func newRateLimitPolicy(cfg config.RateLimit, limiter ratelimit.Limiter, logger *zerolog.Logger) *ratelimit.Policy {
+	if limiter == nil {
+		return nil
+	}
+
+	return ratelimit.NewPolicy(
+		limiter,
+		ratelimit.PerMinute(cfg.MessagesPerMinute, cfg.Burst),
+		ratelimit.PerMinute(cfg.ExpensivePerMinute, cfg.ExpensiveBurst),
+		cfg.NoticeWindow,
+		logger,
+	)
+}
+
+func (s *service) Handle(ctx context.Context, msg *tgbotapi.Message) *response.Response {
 	return s.messageHandler.Handle(ctx, msg)
 }
+
+func (s *service) HandleInline(ctx context.Context, query *tgbotapi.InlineQuery) *response.InlineAnswer {
+	return s.inlineHandler.Handle(ctx, query)
+}
+
+func (s *service) MarkUnreachable(ctx context.Context, telegramID int64) {
+	// Ошибку записывает в лог сервис; отметка повторится при следующей неудачной отправке
+	_ = s.userService.MarkInactive(ctx, telegramID)
+}
+
+func (s *service) Commands() []tgbotapi.BotCommand {
+	return s.router.BotCommands()
+}
+
+func (s *service) APIHandler() http.Handler {
+	return s.apiHandler
+}
+
+func (s *service) ChartsHandler() http.Handler {
+	return s.chartsHandler
+}
+
+func (s *service) RunJobs(ctx context.Context, sender scheduler.Sender) {
+	s.scheduler.Run(ctx, sender)
+}
+
+func (s *service) HealthChecks() []HealthCheck {
+	return []HealthCheck{
+		{Name: "postgres", Ping: s.db.Ping},
+		{Name: "redis", Ping: s.cache.Ping},
+	}
+}
+
+func (s *service) Close(ctx context.Context) error {
+	var errs []error
+
+	if err := s.bg.Wait(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("wait background operations: %w", err))
+	}
+	if err := s.cache.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close redis: %w", err))
+	}
+	if err := s.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close postgres: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,3 @@
+-- Роль пользователя и блокировка. Роль администратора выставляется ботом по списку admins из конфигурации
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS banned_at TIMESTAMPTZ;
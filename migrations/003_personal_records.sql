@@ -0,0 +1,11 @@
+-- Личные рекорды пользователя, по строке на вид рекорда. Кроме рекордов (max_set, max_day,
+-- max_week, longest_streak) здесь хранится текущий прогресс (current_week, current_streak),
+-- чтобы проверка рекорда после подхода не читала всю историю
+CREATE TABLE IF NOT EXISTS personal_records (
+    user_id     BIGINT      NOT NULL,
+    kind        TEXT        NOT NULL,
+    value       INTEGER     NOT NULL,
+    achieved_on DATE        NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, kind)
+);
@@ -0,0 +1,14 @@
+-- Участие пользователя в программе тренировок. Одна активная программа на пользователя,
+-- определения программ хранятся в коде
+CREATE TABLE IF NOT EXISTS program_enrollments (
+    user_id           BIGINT PRIMARY KEY,
+    program_id        TEXT        NOT NULL,
+    initial_max       INTEGER     NOT NULL DEFAULT 0,
+    week              INTEGER     NOT NULL DEFAULT 0,
+    day               INTEGER     NOT NULL DEFAULT 0,
+    misses            INTEGER     NOT NULL DEFAULT 0,
+    day_started_on    DATE,
+    last_completed_on DATE,
+    finished_at       TIMESTAMPTZ,
+    enrolled_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,2 @@
+-- Таймер отдыха между подходами: интервал в секундах, 0 — таймер выключен
+ALTER TABLE users ADD COLUMN IF NOT EXISTS rest_timer_seconds INTEGER NOT NULL DEFAULT 0;
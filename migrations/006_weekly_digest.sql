@@ -0,0 +1,13 @@
+-- Еженедельная сводка: подписка, часовой пояс пользователя и дневная цель
+ALTER TABLE users ADD COLUMN IF NOT EXISTS weekly_digest BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS daily_goal INTEGER NOT NULL DEFAULT 0;
+
+-- Отправленные сводки: строка вставляется до отправки, поэтому неделя отправляется один раз,
+-- даже если ботов несколько
+CREATE TABLE IF NOT EXISTS weekly_digests (
+    user_id    BIGINT      NOT NULL,
+    week_start DATE        NOT NULL,
+    sent_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, week_start)
+);
@@ -0,0 +1,8 @@
+-- Журнал удаления аккаунтов по /deleteme: кто и когда удалил данные и сколько подходов удалено.
+-- Сами данные не сохраняются
+CREATE TABLE IF NOT EXISTS account_deletions (
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    approaches INTEGER     NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
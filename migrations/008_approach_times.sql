@@ -0,0 +1,5 @@
+-- Каждая строка azhumania — один подход со временем записи. До этой миграции время подходов
+-- не хранилось: у старых строк created_at остается NULL
+ALTER TABLE azhumania ADD COLUMN IF NOT EXISTS id BIGSERIAL;
+ALTER TABLE azhumania ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ;
+ALTER TABLE azhumania ALTER COLUMN created_at SET DEFAULT now();
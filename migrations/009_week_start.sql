@@ -0,0 +1,2 @@
+-- Первый день недели в статистике пользователя: номер дня по ISO 8601, 1 — понедельник, 7 — воскресенье
+ALTER TABLE users ADD COLUMN IF NOT EXISTS week_start SMALLINT NOT NULL DEFAULT 1;
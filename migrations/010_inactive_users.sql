@@ -0,0 +1,3 @@
+-- Время, когда Telegram ответил, что бот заблокирован пользователем или чат не найден.
+-- Таким пользователям не отправляются рассылки и сводки, пока они снова не напишут боту
+ALTER TABLE users ADD COLUMN IF NOT EXISTS inactive_at TIMESTAMPTZ;